@@ -0,0 +1,101 @@
+package arrow
+
+import (
+	"context"
+	"sync/atomic"
+
+	arrow "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// batchReader is an array.RecordReader that builds each record batch on
+// demand. It stops as soon as its context is done, reporting the context
+// error from Err, and runs its cleanup function once iteration ends or the
+// reader is released, whichever comes first. Building each batch is traced
+// as a span named after the reader.
+type batchReader struct {
+	refCount int64
+	ctx      context.Context
+	name     string
+	schema   *arrow.Schema
+	next     func() (arrow.Record, error)
+	cleanup  func()
+	cur      arrow.Record
+	err      error
+	done     bool
+}
+
+// newBatchReader creates a reader that calls next for every batch. next
+// returns a nil record once the data is exhausted. cleanup may be nil. name
+// names the span recorded for each call of next.
+func newBatchReader(ctx context.Context, name string, schema *arrow.Schema, next func() (arrow.Record, error), cleanup func()) array.RecordReader {
+	return &batchReader{
+		refCount: 1,
+		ctx:      ctx,
+		name:     name,
+		schema:   schema,
+		next:     next,
+		cleanup:  cleanup,
+	}
+}
+
+func (r *batchReader) Retain() {
+	atomic.AddInt64(&r.refCount, 1)
+}
+
+func (r *batchReader) Release() {
+	if atomic.AddInt64(&r.refCount, -1) == 0 {
+		r.finish()
+	}
+}
+
+func (r *batchReader) Schema() *arrow.Schema { return r.schema }
+func (r *batchReader) Record() arrow.Record  { return r.cur }
+func (r *batchReader) Err() error            { return r.err }
+
+func (r *batchReader) Next() bool {
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+	if r.done {
+		return false
+	}
+	if err := r.ctx.Err(); err != nil {
+		r.err = err
+		r.finish()
+		return false
+	}
+
+	_, span := tracer.Start(r.ctx, r.name)
+	rec, err := r.next()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else if rec != nil {
+		span.SetAttributes(attribute.Int64("arrow.rows", rec.NumRows()))
+	}
+	span.End()
+	if err != nil || rec == nil {
+		r.err = err
+		r.finish()
+		return false
+	}
+	r.cur = rec
+	return true
+}
+
+func (r *batchReader) finish() {
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+	if !r.done {
+		r.done = true
+		if r.cleanup != nil {
+			r.cleanup()
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package arrow
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Dataset is a named ArrowService registered in a Catalog.
+type Dataset struct {
+	Name        string
+	Description string
+	Service     ArrowService
+	Metadata    map[string]string
+}
+
+// RowEstimator is implemented by services that know roughly how many rows
+// they will produce.
+type RowEstimator interface {
+	EstimatedRows() int64
+}
+
+// EstimatedRows returns the dataset's expected row count, or -1 if its
+// service cannot estimate it.
+func (d Dataset) EstimatedRows() int64 {
+	if e, ok := d.Service.(RowEstimator); ok {
+		return e.EstimatedRows()
+	}
+	return -1
+}
+
+// Snapshotter is implemented by services that produce the same batches on
+// every read, for as long as their data is unchanged, so that a stream can
+// be resumed part way through with ReadOptions.StartBatch.
+type Snapshotter interface {
+	// Snapshot identifies the current version of the data. It is empty when
+	// the service cannot promise that two reads return the same batches.
+	Snapshot() string
+}
+
+// Snapshot returns the version of the dataset's data, or "" if its service
+// is not a Snapshotter.
+func (d Dataset) Snapshot() string {
+	if s, ok := d.Service.(Snapshotter); ok {
+		return s.Snapshot()
+	}
+	return ""
+}
+
+// HealthChecker is implemented by services that can tell whether they are
+// able to serve data, beyond producing their schema.
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
+// CheckHealth reports whether the dataset can be served: its service must
+// produce a schema and, if it is a HealthChecker, pass its own check.
+func (d Dataset) CheckHealth(ctx context.Context) error {
+	if _, err := ServiceSchema(ctx, d.Service); err != nil {
+		return err
+	}
+	if h, ok := d.Service.(HealthChecker); ok {
+		return h.CheckHealth(ctx)
+	}
+	return nil
+}
+
+// Catalog holds the datasets served by one ArrowLink process. The first
+// registered dataset is the default, used when a request names no dataset.
+type Catalog struct {
+	mu       sync.RWMutex
+	datasets map[string]Dataset
+	first    string
+}
+
+// NewCatalog creates an empty catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		datasets: make(map[string]Dataset),
+	}
+}
+
+// Register adds a dataset. Names must be non-empty and unique.
+func (c *Catalog) Register(ds Dataset) error {
+	if ds.Name == "" {
+		return fmt.Errorf("dataset name must not be empty")
+	}
+	if ds.Service == nil {
+		return fmt.Errorf("dataset %q has no service", ds.Name)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.datasets[ds.Name]; ok {
+		return fmt.Errorf("dataset %q is already registered", ds.Name)
+	}
+	c.datasets[ds.Name] = ds
+	if c.first == "" {
+		c.first = ds.Name
+	}
+	return nil
+}
+
+// Lookup returns the dataset with the given name. An empty name selects the
+// default dataset.
+func (c *Catalog) Lookup(name string) (Dataset, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if name == "" {
+		name = c.first
+	}
+	ds, ok := c.datasets[name]
+	return ds, ok
+}
+
+// List returns every registered dataset, sorted by name.
+func (c *Catalog) List() []Dataset {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	list := make([]Dataset, 0, len(c.datasets))
+	for _, ds := range c.datasets {
+		list = append(list, ds)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
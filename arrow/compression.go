@@ -0,0 +1,58 @@
+package arrow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+)
+
+// Codec identifies the compression applied to IPC record batch bodies.
+type Codec string
+
+const (
+	CodecNone     Codec = "none"
+	CodecLZ4Frame Codec = "lz4_frame"
+	CodecZstd     Codec = "zstd"
+)
+
+// ParseCodec parses a codec name. It accepts "none", "lz4", "lz4_frame" and
+// "zstd" in any case; an empty name parses as CodecNone.
+func ParseCodec(name string) (Codec, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "none", "uncompressed":
+		return CodecNone, nil
+	case "lz4", "lz4_frame", "lz4-frame":
+		return CodecLZ4Frame, nil
+	case "zstd":
+		return CodecZstd, nil
+	default:
+		return "", fmt.Errorf("unknown compression codec %q", name)
+	}
+}
+
+// IPCOptions returns the IPC writer options that enable the codec.
+func (c Codec) IPCOptions() []ipc.Option {
+	switch c {
+	case CodecLZ4Frame:
+		return []ipc.Option{ipc.WithLZ4()}
+	case CodecZstd:
+		return []ipc.Option{ipc.WithZstd()}
+	default:
+		return nil
+	}
+}
+
+// ValidateLevel checks a requested compression level. Zero selects the
+// codec's default level and is the only level accepted: arrow-go's IPC writer
+// always compresses at the default level, and a level that would be ignored
+// is rejected rather than silently dropped.
+func (c Codec) ValidateLevel(level int) error {
+	if level == 0 {
+		return nil
+	}
+	if c == CodecNone {
+		return fmt.Errorf("compression level %d given without a codec", level)
+	}
+	return fmt.Errorf("compression level %d is not supported: %s always uses its default level", level, c)
+}
@@ -0,0 +1,25 @@
+package arrow
+
+import "testing"
+
+func TestValidateLevel(t *testing.T) {
+	tests := []struct {
+		codec   Codec
+		level   int
+		wantErr bool
+	}{
+		{CodecNone, 0, false},
+		{CodecLZ4Frame, 0, false},
+		{CodecZstd, 0, false},
+		{CodecNone, 1, true},
+		{CodecLZ4Frame, 9, true},
+		{CodecZstd, 3, true},
+		{CodecZstd, -1, true},
+	}
+	for _, tt := range tests {
+		err := tt.codec.ValidateLevel(tt.level)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s.ValidateLevel(%d) = %v, want error %t", tt.codec, tt.level, err, tt.wantErr)
+		}
+	}
+}
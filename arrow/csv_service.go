@@ -0,0 +1,249 @@
+package arrow
+
+import (
+	"context"
+	stdcsv "encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	arrow "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/csv"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// DefaultCSVInferRows is the number of rows sampled to infer the types of
+// CSV columns unless CSVOptions.InferRows says otherwise.
+const DefaultCSVInferRows = 1000
+
+// CSVOptions configures a CSV-backed service.
+type CSVOptions struct {
+	// Schema gives the column names and types. When nil, names are taken
+	// from the header and types are inferred from the first InferRows data
+	// rows of the first file.
+	Schema *arrow.Schema
+	// InferRows is the number of rows sampled to infer the column types.
+	// Zero selects DefaultCSVInferRows and a negative value reads the whole
+	// file. Ignored when Schema is set.
+	InferRows int
+	// NoHeader marks files that start directly with data. It requires an
+	// explicit Schema.
+	NoHeader bool
+	// Comma is the field delimiter. Zero selects ','.
+	Comma rune
+	// NullValues lists the strings read as null. Nil selects
+	// csv.DefaultNullValues: "", "NULL" and "null".
+	NullValues []string
+}
+
+type csvService struct {
+	mem    memory.Allocator
+	files  []string
+	schema *arrow.Schema
+	opts   CSVOptions
+}
+
+// NewCSVService serves a CSV file, or every .csv file under a directory, as a
+// single dataset. Every file must have the same columns. Files are parsed
+// lazily as the reader returned by GetData advances.
+func NewCSVService(path string, opts CSVOptions) (ArrowService, error) {
+	if opts.NoHeader && opts.Schema == nil {
+		return nil, fmt.Errorf("%s: a schema is required for CSV files without a header", path)
+	}
+	files, err := listFiles(path, FormatCSV)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &csvService{
+		mem:    memory.NewGoAllocator(),
+		files:  files,
+		schema: opts.Schema,
+		opts:   opts,
+	}
+	if s.schema == nil {
+		if s.schema, err = s.inferSchema(files[0]); err != nil {
+			return nil, fmt.Errorf("%s: %w", files[0], err)
+		}
+	} else if err := validateCSVSchema(s.schema); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// CheckHealth reports whether every file is still readable.
+func (s *csvService) CheckHealth(ctx context.Context) error {
+	return checkFiles(s.files)
+}
+
+// Snapshot changes whenever a file is modified.
+func (s *csvService) Snapshot() string {
+	return filesSnapshot(s.files)
+}
+
+func (s *csvService) GetData(ctx context.Context, opts ReadOptions) (array.RecordReader, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	readerOpts := append(s.readerOptions(),
+		csv.WithChunk(opts.batchSize()),
+		csv.WithHeader(!s.opts.NoHeader),
+		csv.WithAllocator(opts.allocator(s.mem)),
+	)
+	open := func(ctx context.Context, path string) (array.RecordReader, io.Closer, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return csv.NewReader(f, s.schema, readerOpts...), f, nil
+	}
+	// The CSV reader renames fields after each file's header; batches are
+	// rebuilt with the service schema so every file yields the same one.
+	conform := func(rec arrow.Record) (arrow.Record, error) {
+		return array.NewRecord(s.schema, rec.Columns(), rec.NumRows()), nil
+	}
+	return readFiles(ctx, s.schema, s.files, opts.StartBatch, open, conform), nil
+}
+
+// csvInferredTypes are the types a CSV column can be inferred as, from the
+// most to the least specific. They follow csv.NewInferringReader, which
+// only looks at the first row.
+var csvInferredTypes = []arrow.DataType{
+	arrow.PrimitiveTypes.Int64,
+	arrow.FixedWidthTypes.Boolean,
+	arrow.FixedWidthTypes.Date32,
+	arrow.FixedWidthTypes.Time32s,
+	&arrow.TimestampType{Unit: arrow.Second},
+	&arrow.TimestampType{Unit: arrow.Nanosecond},
+	arrow.PrimitiveTypes.Float64,
+	arrow.BinaryTypes.String,
+}
+
+// inferSchema reads the header and the first data rows of path, and gives
+// each column the first of csvInferredTypes that every non-null value in
+// the sample parses as. A column with only nulls is a string.
+//
+// Later types do not accept every value of earlier ones ("true" is a
+// boolean but not a date), so each column tracks every type its values
+// still allow rather than a single candidate.
+func (s *csvService) inferSchema(path string) (*arrow.Schema, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := stdcsv.NewReader(f)
+	r.ReuseRecord = true
+	if s.opts.Comma != 0 {
+		r.Comma = s.opts.Comma
+	}
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("cannot infer schema: %w", err)
+	}
+	names := slices.Clone(header)
+	// ruledOut[i][t] is set once a value of column i does not parse as
+	// csvInferredTypes[t]
+	ruledOut := make([][]bool, len(names))
+	for i := range ruledOut {
+		ruledOut[i] = make([]bool, len(csvInferredTypes))
+	}
+	seen := make([]bool, len(names))
+
+	limit := s.opts.InferRows
+	if limit == 0 {
+		limit = DefaultCSVInferRows
+	}
+	nulls := s.opts.NullValues
+	if len(nulls) == 0 {
+		nulls = csv.DefaultNullValues
+	}
+	rows := 0
+	for ; limit < 0 || rows < limit; rows++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot infer schema: %w", err)
+		}
+		for i, v := range record {
+			if slices.Contains(nulls, v) {
+				continue
+			}
+			seen[i] = true
+			for t, dt := range csvInferredTypes {
+				if !ruledOut[i][t] && !parsesAs(v, dt) {
+					ruledOut[i][t] = true
+				}
+			}
+		}
+	}
+	if rows == 0 {
+		return nil, fmt.Errorf("cannot infer schema: no data rows")
+	}
+
+	fields := make([]arrow.Field, len(names))
+	for i, name := range names {
+		// String is never ruled out
+		t := slices.Index(ruledOut[i], false)
+		if !seen[i] {
+			t = len(csvInferredTypes) - 1
+		}
+		fields[i] = arrow.Field{Name: name, Type: csvInferredTypes[t], Nullable: true}
+	}
+	return arrow.NewSchema(fields, nil), nil
+}
+
+// parsesAs reports whether the CSV reader can parse v as a value of type dt,
+// one of csvInferredTypes.
+func parsesAs(v string, dt arrow.DataType) bool {
+	var err error
+	switch dt := dt.(type) {
+	case *arrow.Int64Type:
+		_, err = strconv.ParseInt(v, 10, 64)
+	case *arrow.BooleanType:
+		_, err = strconv.ParseBool(v)
+	case *arrow.Date32Type:
+		_, err = time.Parse("2006-01-02", v)
+	case *arrow.Time32Type:
+		_, err = arrow.Time32FromString(v, dt.Unit)
+	case *arrow.TimestampType:
+		_, err = arrow.TimestampFromString(v, dt.Unit)
+	case *arrow.Float64Type:
+		_, err = strconv.ParseFloat(v, 64)
+	case *arrow.StringType:
+		return true
+	}
+	return err == nil
+}
+
+func (s *csvService) readerOptions() []csv.Option {
+	opts := []csv.Option{
+		csv.WithAllocator(s.mem),
+		csv.WithNullReader(true, s.opts.NullValues...),
+	}
+	if s.opts.Comma != 0 {
+		opts = append(opts, csv.WithComma(s.opts.Comma))
+	}
+	return opts
+}
+
+// validateCSVSchema reports schemas the CSV reader cannot parse into, which
+// csv.NewReader would otherwise panic on.
+func validateCSVSchema(schema *arrow.Schema) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("unsupported CSV schema: %v", r)
+		}
+	}()
+	csv.NewReader(strings.NewReader(""), schema).Release()
+	return nil
+}
@@ -1,94 +1,142 @@
 package arrow
 
 import (
-	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"math/rand"
 	"time"
 
 	arrow "github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
-	"github.com/apache/arrow-go/v18/arrow/ipc"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 )
 
-type Metrics struct {
-	GenerationTime    time.Duration
-	SerializationTime time.Duration
-}
-
+// DemoArrowService generates synthetic data following a GeneratorSpec.
 type DemoArrowService struct {
 	mem      memory.Allocator
 	dataSize int
-	metrics  Metrics
+	spec     GeneratorSpec
+	schema   *arrow.Schema
 }
 
+// seededEpoch is the first timestamp of the timestamp columns of a seeded
+// spec that do not set From.
+var seededEpoch = time.Unix(0, 0).UTC()
+
+// NewDemoArrowService generates dataSize rows following DefaultGeneratorSpec.
 func NewDemoArrowService(dataSize int) ArrowService {
+	s, err := NewDemoArrowServiceFromSpec(DefaultGeneratorSpec(), dataSize)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// NewDemoArrowServiceFromSpec generates dataSize rows following spec.
+func NewDemoArrowServiceFromSpec(spec GeneratorSpec, dataSize int) (ArrowService, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
 	return &DemoArrowService{
 		mem:      memory.NewGoAllocator(),
 		dataSize: dataSize,
-	}
+		spec:     spec,
+		schema:   spec.Schema(),
+	}, nil
+}
+
+func (s *DemoArrowService) EstimatedRows() int64 {
+	return int64(s.dataSize)
 }
 
-func (s *DemoArrowService) GetMetrics() Metrics {
-	return s.metrics
+// Snapshot identifies the data of a seeded service by its spec, which
+// includes the seed, and its size, so that every process serving the same
+// dataset agrees on it. An unseeded service generates new data on every
+// read and has no snapshot.
+func (s *DemoArrowService) Snapshot() string {
+	if s.spec.Seed == nil {
+		return ""
+	}
+	spec, err := json.Marshal(s.spec)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(fmt.Appendf(spec, "|%d", s.dataSize))
+	return hex.EncodeToString(sum[:12])
 }
 
-func (s *DemoArrowService) GetData() ([]byte, error) {
-	startGen := time.Now()
-
-	// Define a more complex Arrow schema with various data types
-	schema := arrow.NewSchema([]arrow.Field{
-		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
-		{Name: "timestamp", Type: arrow.FixedWidthTypes.Timestamp_ms},
-		{Name: "value", Type: arrow.PrimitiveTypes.Float64},
-		{Name: "category", Type: arrow.BinaryTypes.String},
-		{Name: "is_valid", Type: arrow.FixedWidthTypes.Boolean},
-	}, nil)
-
-	// Create record builder
-	builder := array.NewRecordBuilder(s.mem, schema)
-	defer builder.Release()
-
-	// Get builders for each field
-	idBuilder := builder.Field(0).(*array.Int64Builder)
-	tsBuilder := builder.Field(1).(*array.TimestampBuilder)
-	valueBuilder := builder.Field(2).(*array.Float64Builder)
-	categoryBuilder := builder.Field(3).(*array.StringBuilder)
-	validBuilder := builder.Field(4).(*array.BooleanBuilder)
-
-	// Generate random data
-	rand.Seed(time.Now().UnixNano())
-	categories := []string{"A", "B", "C", "D", "E"}
-	now := time.Now()
-
-	// Populate with random data
-	for i := 0; i < s.dataSize; i++ {
-		idBuilder.Append(int64(i))
-		tsBuilder.Append(arrow.Timestamp(now.Add(time.Duration(i)*time.Second).UnixNano() / int64(time.Millisecond)))
-		valueBuilder.Append(rand.Float64() * 100)
-		categoryBuilder.Append(categories[rand.Intn(len(categories))])
-		validBuilder.Append(rand.Intn(10) > 2) // 70% valid
+// GetData builds batches concurrently on opts.Parallelism workers, each with
+// its own builder and random source, and returns them in order. Building
+// starts with the first call to Next and then runs ahead of the caller by up
+// to one batch per worker, so that it overlaps with whatever the caller does
+// with each batch.
+func (s *DemoArrowService) GetData(ctx context.Context, opts ReadOptions) (array.RecordReader, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	batchSize := opts.batchSize()
+	batches := (int64(s.dataSize) + int64(batchSize) - 1) / int64(batchSize)
+	mem := opts.allocator(s.mem)
+
+	// Each batch draws from its own stream of the seed, so that a seeded
+	// dataset does not depend on which worker builds which batch, and can
+	// start at any batch. Its timestamps count from a fixed epoch rather
+	// than the clock, so that it is the same in every process
+	seed, from := time.Now().UnixNano(), time.Now()
+	if s.spec.Seed != nil {
+		seed, from = *s.spec.Seed, seededEpoch
 	}
 
-	record := builder.NewRecord()
-	defer record.Release()
+	next, stop := parallelBatches(ctx, opts.StartBatch, batches, opts.parallelism(), func() batchWorker {
+		builder := array.NewRecordBuilder(mem, s.schema)
+		rng := rand.New(rand.NewSource(seed))
+		return &demoWorker{
+			builder:   builder,
+			rng:       rng,
+			generate:  s.spec.generator(builder, rng, from),
+			seed:      seed,
+			rows:      s.dataSize,
+			batchSize: batchSize,
+		}
+	})
+
+	return newBatchReader(ctx, "DemoArrowService.BuildBatch", s.schema, next, stop), nil
+}
 
-	// After record creation
-	s.metrics.GenerationTime = time.Since(startGen)
+// demoWorker builds the batches of a DemoArrowService read.
+type demoWorker struct {
+	builder   *array.RecordBuilder
+	rng       *rand.Rand
+	generate  columnGenerator
+	seed      int64
+	rows      int
+	batchSize int
+}
 
-	// Before serialization
-	startSer := time.Now()
+func (w *demoWorker) build(batch int64) (arrow.Record, error) {
+	start := int(batch) * w.batchSize
+	end := min(start+w.batchSize, w.rows)
+	w.builder.Reserve(end - start)
 
-	// Serialize record using Arrow IPC
-	var buf bytes.Buffer
-	writer := ipc.NewWriter(&buf, ipc.WithSchema(schema))
-	if err := writer.Write(record); err != nil {
-		return nil, err
+	w.rng.Seed(batchSeed(w.seed, batch))
+	for i := start; i < end; i++ {
+		w.generate(int64(i))
 	}
-	writer.Close()
+	return w.builder.NewRecord(), nil
+}
 
-	// After serialization
-	s.metrics.SerializationTime = time.Since(startSer)
+func (w *demoWorker) release() {
+	w.builder.Release()
+}
 
-	return buf.Bytes(), nil
+// batchSeed derives the seed of a batch from the dataset's seed with the
+// SplitMix64 finalizer, so that neighbouring batches get unrelated streams.
+func batchSeed(seed, batch int64) int64 {
+	z := uint64(seed) + uint64(batch+1)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
 }
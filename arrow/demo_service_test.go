@@ -0,0 +1,115 @@
+package arrow
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// demoIPC reads every batch of a demo service built from spec and returns
+// them serialized.
+func demoIPC(t *testing.T, spec GeneratorSpec, rows int, opts ReadOptions) [][]byte {
+	t.Helper()
+	service, err := NewDemoArrowServiceFromSpec(spec, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := service.GetData(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Release()
+	var batches [][]byte
+	for reader.Next() {
+		payload, err := SerializeRecord(reader.Record(), CodecNone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		batches = append(batches, payload)
+	}
+	if err := reader.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return batches
+}
+
+func seededSpec(seed int64) GeneratorSpec {
+	spec := DefaultGeneratorSpec()
+	spec.Seed = &seed
+	return spec
+}
+
+func TestSeededDemoIsReproducible(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+	opts := ReadOptions{BatchSize: 100, Allocator: mem, Parallelism: 4}
+
+	first := demoIPC(t, seededSpec(42), 1050, opts)
+	// The default spec's timestamps have no From; a later service must
+	// still start them at the same time.
+	time.Sleep(10 * time.Millisecond)
+	second := demoIPC(t, seededSpec(42), 1050, opts)
+	opts.Parallelism = 1
+	serial := demoIPC(t, seededSpec(42), 1050, opts)
+
+	if len(first) != 11 {
+		t.Fatalf("got %d batches, want 11", len(first))
+	}
+	for i := range first {
+		if !bytes.Equal(first[i], second[i]) {
+			t.Errorf("batch %d differs between two services with the same seed", i)
+		}
+		if !bytes.Equal(first[i], serial[i]) {
+			t.Errorf("batch %d differs between 4 workers and 1", i)
+		}
+	}
+
+	other := demoIPC(t, seededSpec(43), 1050, opts)
+	if bytes.Equal(first[0], other[0]) {
+		t.Error("different seeds produced the same batch")
+	}
+}
+
+func TestSeededDemoStartBatch(t *testing.T) {
+	opts := ReadOptions{BatchSize: 100}
+	all := demoIPC(t, seededSpec(7), 1050, opts)
+	for _, start := range []int64{1, 5, 10, 11, 20} {
+		opts.StartBatch = start
+		tail := demoIPC(t, seededSpec(7), 1050, opts)
+		want := all[min(int(start), len(all)):]
+		if len(tail) != len(want) {
+			t.Errorf("StartBatch %d: got %d batches, want %d", start, len(tail), len(want))
+			continue
+		}
+		for i := range tail {
+			if !bytes.Equal(tail[i], want[i]) {
+				t.Errorf("StartBatch %d: batch %d differs", start, i)
+			}
+		}
+	}
+}
+
+func TestDemoSnapshot(t *testing.T) {
+	snapshot := func(spec GeneratorSpec, rows int) string {
+		t.Helper()
+		service, err := NewDemoArrowServiceFromSpec(spec, rows)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return service.(*DemoArrowService).Snapshot()
+	}
+	if got := snapshot(DefaultGeneratorSpec(), 100); got != "" {
+		t.Errorf("unseeded snapshot = %q, want none", got)
+	}
+	first := snapshot(seededSpec(1), 100)
+	time.Sleep(10 * time.Millisecond)
+	if first == "" || snapshot(seededSpec(1), 100) != first {
+		t.Errorf("two services with the same seed disagree on the snapshot")
+	}
+	if snapshot(seededSpec(2), 100) == first || snapshot(seededSpec(1), 101) == first {
+		t.Error("snapshot does not depend on the seed and size")
+	}
+}
@@ -0,0 +1,230 @@
+package arrow
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	arrow "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// FileFormat identifies the on-disk format of a file-backed dataset.
+type FileFormat string
+
+const (
+	FormatCSV     FileFormat = "csv"
+	FormatParquet FileFormat = "parquet"
+)
+
+// formatOf returns the format implied by a file's extension, or "" if the
+// extension is not recognized.
+func formatOf(path string) FileFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV
+	case ".parquet", ".pq":
+		return FormatParquet
+	default:
+		return ""
+	}
+}
+
+// DetectFormat returns the format of a file, or of the files in a directory.
+// A directory must hold files of a single format.
+func DetectFormat(path string) (FileFormat, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		if f := formatOf(path); f != "" {
+			return f, nil
+		}
+		return "", fmt.Errorf("%s: unrecognized file extension", path)
+	}
+
+	var found FileFormat
+	err = walkDataFiles(path, func(p string) error {
+		f := formatOf(p)
+		if f == "" {
+			return nil
+		}
+		if found != "" && found != f {
+			return fmt.Errorf("%s holds both %s and %s files", path, found, f)
+		}
+		found = f
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if found == "" {
+		return "", fmt.Errorf("%s holds no CSV or Parquet files", path)
+	}
+	return found, nil
+}
+
+// NewFileService serves the CSV or Parquet data at path, which may be a
+// single file or a directory. The format is detected from file extensions,
+// and a nil schema is inferred from the data.
+func NewFileService(path string, schema *arrow.Schema) (ArrowService, error) {
+	format, err := DetectFormat(path)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case FormatCSV:
+		return NewCSVService(path, CSVOptions{Schema: schema})
+	default:
+		return NewParquetService(path, ParquetOptions{Schema: schema})
+	}
+}
+
+// walkDataFiles calls fn for every regular file under root in lexical order,
+// skipping hidden and underscore-prefixed entries such as _SUCCESS markers.
+func walkDataFiles(root string, fn func(path string) error) error {
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if p != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() {
+			return fn(p)
+		}
+		return nil
+	})
+}
+
+// listFiles returns path itself if it is a file, or every file of the given
+// format under it if it is a directory.
+func listFiles(path string, format FileFormat) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = walkDataFiles(path, func(p string) error {
+		if formatOf(p) == format {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%s holds no %s files", path, format)
+	}
+	return files, nil
+}
+
+// checkFiles reports the first of files that can no longer be read.
+func checkFiles(files []string) error {
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		f.Close()
+	}
+	return nil
+}
+
+// filesSnapshot identifies the contents of files by their paths, sizes and
+// modification times, or returns "" if one of them cannot be examined.
+func filesSnapshot(files []string) string {
+	h := sha256.New()
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			return ""
+		}
+		fmt.Fprintf(h, "%s|%d|%d\n", path, info.Size(), info.ModTime().UnixNano())
+	}
+	return hex.EncodeToString(h.Sum(nil)[:12])
+}
+
+// fileOpener opens one file as a record reader. The closer is closed once the
+// reader has been released.
+type fileOpener func(ctx context.Context, path string) (array.RecordReader, io.Closer, error)
+
+// readFiles returns a reader that streams the batches of each file in turn,
+// opening a file only once the previous one is exhausted. conform turns each
+// batch into a new record with the given schema, or rejects it. The first
+// skip batches are read and discarded.
+func readFiles(ctx context.Context, schema *arrow.Schema, files []string, skip int64, open fileOpener, conform func(arrow.Record) (arrow.Record, error)) array.RecordReader {
+	var (
+		idx    int
+		cur    array.RecordReader
+		closer io.Closer
+	)
+	closeCurrent := func() {
+		if cur != nil {
+			cur.Release()
+			closer.Close()
+			cur, closer = nil, nil
+		}
+	}
+
+	next := func() (arrow.Record, error) {
+		for {
+			if cur == nil {
+				if idx == len(files) {
+					return nil, nil
+				}
+				r, c, err := open(ctx, files[idx])
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", files[idx], err)
+				}
+				cur, closer = r, c
+			}
+
+			// The CSV reader reports parse errors alongside a batch, and
+			// pqarrow ends its files with io.EOF.
+			ok := cur.Next()
+			err := cur.Err()
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			if ok && err == nil {
+				if cur.Record().NumRows() == 0 {
+					continue
+				}
+				if skip > 0 {
+					skip--
+					continue
+				}
+				rec, err := conform(cur.Record())
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", files[idx], err)
+				}
+				return rec, nil
+			}
+
+			closeCurrent()
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", files[idx], err)
+			}
+			idx++
+		}
+	}
+	return newBatchReader(ctx, "FileService.ReadBatch", schema, next, closeCurrent)
+}
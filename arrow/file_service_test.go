@@ -0,0 +1,328 @@
+package arrow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	arrow "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+)
+
+func writeTestFile(t *testing.T, path, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// writeTestParquet writes ids first to first+n-1 and their names to path, in
+// row groups of 3 rows.
+func writeTestParquet(t *testing.T, path string, first, n int64) string {
+	t.Helper()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "name", Type: arrow.BinaryTypes.String},
+	}, nil)
+	builder := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer builder.Release()
+	for i := first; i < first+n; i++ {
+		builder.Field(0).(*array.Int64Builder).Append(i)
+		builder.Field(1).(*array.StringBuilder).Append("name")
+	}
+	record := builder.NewRecord()
+	defer record.Release()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := pqarrow.NewFileWriter(schema, f, parquet.NewWriterProperties(parquet.WithMaxRowGroupLength(3)), pqarrow.DefaultWriterProps())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteBuffered(record); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// readColumn reads every batch of svc and returns the values of its int64
+// column named col.
+func readColumn(t *testing.T, svc ArrowService, opts ReadOptions, col string) []int64 {
+	t.Helper()
+	reader, err := svc.GetData(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Release()
+	indices := reader.Schema().FieldIndices(col)
+	if len(indices) != 1 {
+		t.Fatalf("schema %s has no column %q", reader.Schema(), col)
+	}
+	values := []int64{}
+	for reader.Next() {
+		values = append(values, reader.Record().Column(indices[0]).(*array.Int64).Int64Values()...)
+	}
+	if err := reader.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return values
+}
+
+func schemaTypes(schema *arrow.Schema) map[string]string {
+	types := make(map[string]string, schema.NumFields())
+	for _, f := range schema.Fields() {
+		types[f.Name] = f.Type.String()
+	}
+	return types
+}
+
+func TestCSVInferSchema(t *testing.T) {
+	// Every column but id is null or misleading in the first row
+	path := writeTestFile(t, filepath.Join(t.TempDir(), "data.csv"), strings.Join([]string{
+		"id,flag,day,amount,note,mixed,empty",
+		"1,,2024-01-01,5,,true,",
+		"2,true,2024-01-02,2.5,x,2024-01-01,NULL",
+		"3,false,,7,yes,false,",
+		"",
+	}, "\n"))
+	svc, err := NewCSVService(path, CSVOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := svc.GetData(context.Background(), ReadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Release()
+
+	want := map[string]string{
+		"id":     "int64",
+		"flag":   "bool",
+		"day":    "date32",
+		"amount": "float64",
+		"note":   "utf8",
+		"mixed":  "utf8",
+		"empty":  "utf8",
+	}
+	if got := schemaTypes(reader.Schema()); !reflect.DeepEqual(got, want) {
+		t.Errorf("inferred types = %v, want %v", got, want)
+	}
+	rows := 0
+	for reader.Next() {
+		rows += int(reader.Record().NumRows())
+	}
+	if err := reader.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if rows != 3 {
+		t.Errorf("read %d rows, want 3", rows)
+	}
+}
+
+func TestCSVInferRows(t *testing.T) {
+	path := writeTestFile(t, filepath.Join(t.TempDir(), "data.csv"), "id,amount\n1,5\n2,6\n3,7.5\n")
+	for _, tc := range []struct {
+		rows int
+		want string
+	}{
+		{0, "float64"},
+		{-1, "float64"},
+		{2, "int64"},
+	} {
+		svc, err := NewCSVService(path, CSVOptions{InferRows: tc.rows})
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader, err := svc.GetData(context.Background(), ReadOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := schemaTypes(reader.Schema())["amount"]; got != tc.want {
+			t.Errorf("InferRows %d: amount is %s, want %s", tc.rows, got, tc.want)
+		}
+		reader.Release()
+	}
+
+	empty := writeTestFile(t, filepath.Join(t.TempDir(), "empty.csv"), "id,amount\n")
+	if _, err := NewCSVService(empty, CSVOptions{}); err == nil || !strings.Contains(err.Error(), "no data rows") {
+		t.Errorf("NewCSVService of a header-only file = %v, want a no data rows error", err)
+	}
+}
+
+func TestCSVExplicitSchema(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "amount", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}, nil)
+	path := writeTestFile(t, filepath.Join(t.TempDir(), "data.csv"), "1;5\n2;\n3;7.5\n")
+	svc, err := NewCSVService(path, CSVOptions{Schema: schema, NoHeader: true, Comma: ';'})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readColumn(t, svc, ReadOptions{}, "id"); !reflect.DeepEqual(got, []int64{1, 2, 3}) {
+		t.Errorf("ids = %v", got)
+	}
+
+	if _, err := NewCSVService(path, CSVOptions{NoHeader: true}); err == nil {
+		t.Error("NewCSVService accepted NoHeader without a schema")
+	}
+	nested := arrow.NewSchema([]arrow.Field{{Name: "tags", Type: arrow.MapOf(arrow.BinaryTypes.String, arrow.BinaryTypes.String)}}, nil)
+	if _, err := NewCSVService(path, CSVOptions{Schema: nested}); err == nil {
+		t.Error("NewCSVService accepted a schema the CSV reader cannot parse into")
+	}
+}
+
+func TestCSVDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "a.csv"), "id,name\n0,a\n1,b\n2,c\n")
+	// Files may name their columns differently; the first file's names win
+	writeTestFile(t, filepath.Join(dir, "b", "c.csv"), "ID,NAME\n3,d\n4,e\n")
+	writeTestFile(t, filepath.Join(dir, "_SUCCESS"), "")
+	writeTestFile(t, filepath.Join(dir, ".hidden.csv"), "id,name\n99,z\n")
+
+	svc, err := NewCSVService(dir, CSVOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readColumn(t, svc, ReadOptions{BatchSize: 2}, "id"); !reflect.DeepEqual(got, []int64{0, 1, 2, 3, 4}) {
+		t.Errorf("ids = %v", got)
+	}
+	// Batches are [0 1] [2] [3 4]
+	if got := readColumn(t, svc, ReadOptions{BatchSize: 2, StartBatch: 2}, "id"); !reflect.DeepEqual(got, []int64{3, 4}) {
+		t.Errorf("ids from batch 2 = %v, want [3 4]", got)
+	}
+}
+
+func TestParquetService(t *testing.T) {
+	dir := t.TempDir()
+	writeTestParquet(t, filepath.Join(dir, "part-0.parquet"), 0, 5)
+	writeTestParquet(t, filepath.Join(dir, "part-1.parquet"), 5, 4)
+
+	svc, err := NewParquetService(dir, ParquetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := svc.(RowEstimator).EstimatedRows(); got != 9 {
+		t.Errorf("EstimatedRows() = %d, want 9", got)
+	}
+	if got := readColumn(t, svc, ReadOptions{BatchSize: 2}, "id"); !reflect.DeepEqual(got, []int64{0, 1, 2, 3, 4, 5, 6, 7, 8}) {
+		t.Errorf("ids = %v", got)
+	}
+
+	// An explicit schema selects and orders columns
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "name", Type: arrow.BinaryTypes.String},
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	svc, err = NewParquetService(dir, ParquetOptions{Schema: schema, Parallel: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := svc.GetData(context.Background(), ReadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reader.Schema().Equal(schema) {
+		t.Errorf("schema = %s, want %s", reader.Schema(), schema)
+	}
+	reader.Release()
+	if got := readColumn(t, svc, ReadOptions{}, "id"); len(got) != 9 {
+		t.Errorf("read %d ids, want 9", len(got))
+	}
+
+	wrong := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.BinaryTypes.String}}, nil)
+	if _, err := NewParquetService(dir, ParquetOptions{Schema: wrong}); err == nil {
+		t.Error("NewParquetService accepted a schema whose types differ from the files")
+	}
+}
+
+func TestFileService(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := writeTestFile(t, filepath.Join(dir, "csv", "data.csv"), "id\n1\n2\n")
+	pqPath := writeTestParquet(t, filepath.Join(dir, "data.parquet"), 0, 3)
+
+	for path, want := range map[string]FileFormat{
+		csvPath:                   FormatCSV,
+		filepath.Dir(csvPath):     FormatCSV,
+		pqPath:                    FormatParquet,
+		filepath.Join(dir, "csv"): FormatCSV,
+	} {
+		if got, err := DetectFormat(path); err != nil || got != want {
+			t.Errorf("DetectFormat(%s) = %q, %v; want %q", path, got, err, want)
+		}
+	}
+	for _, path := range []string{
+		dir, // both formats
+		writeTestFile(t, filepath.Join(dir, "notes.txt"), "id\n1\n"),
+		filepath.Join(dir, "missing.csv"),
+	} {
+		if _, err := NewFileService(path, nil); err == nil {
+			t.Errorf("NewFileService(%s) succeeded", path)
+		}
+	}
+
+	svc, err := NewFileService(csvPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readColumn(t, svc, ReadOptions{}, "id"); !reflect.DeepEqual(got, []int64{1, 2}) {
+		t.Errorf("CSV ids = %v", got)
+	}
+	svc, err = NewFileService(pqPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readColumn(t, svc, ReadOptions{}, "id"); !reflect.DeepEqual(got, []int64{0, 1, 2}) {
+		t.Errorf("Parquet ids = %v", got)
+	}
+}
+
+func TestFileSnapshotAndHealth(t *testing.T) {
+	path := writeTestFile(t, filepath.Join(t.TempDir(), "data.csv"), "id\n1\n")
+	svc, err := NewCSVService(path, CSVOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot := svc.(Snapshotter).Snapshot()
+	if snapshot == "" || snapshot != svc.(Snapshotter).Snapshot() {
+		t.Fatalf("Snapshot() = %q, want a stable snapshot", snapshot)
+	}
+	if err := svc.(HealthChecker).CheckHealth(context.Background()); err != nil {
+		t.Errorf("CheckHealth() = %v", err)
+	}
+
+	writeTestFile(t, path, "id\n1\n2\n")
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if svc.(Snapshotter).Snapshot() == snapshot {
+		t.Error("the snapshot did not change when the file did")
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.(HealthChecker).CheckHealth(context.Background()); err == nil {
+		t.Error("CheckHealth() succeeded after the file was removed")
+	}
+	if got := svc.(Snapshotter).Snapshot(); got != "" {
+		t.Errorf("Snapshot() = %q after the file was removed, want none", got)
+	}
+}
@@ -0,0 +1,679 @@
+package arrow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	arrow "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/scalar"
+)
+
+// Filter is a parsed row filter expression. The grammar is a small SQL-like
+// subset:
+//
+//	expr      = and { OR and }
+//	and       = unary { AND unary }
+//	unary     = NOT unary | primary
+//	primary   = "(" expr ")"
+//	          | operand ( "=" | "!=" | "<>" | "<" | "<=" | ">" | ">=" ) operand
+//	          | field [ NOT ] IN "(" literal { "," literal } ")"
+//	          | field IS [ NOT ] NULL
+//	          | field
+//	operand   = field | literal
+//	literal   = 'string' | number | TRUE | FALSE
+//
+// Fields are bare identifiers or "double-quoted" names. A bare field must be
+// boolean. Literals are converted to the type of the field they are compared
+// with, so timestamps are written as strings such as '2024-01-02T15:04:05'.
+// Comparisons with NULL values yield NULL and such rows are dropped.
+type Filter struct {
+	expr string
+	root filterNode
+}
+
+// ParseFilter parses a filter expression. The result must be bound to a
+// schema with Bind before it can be evaluated.
+func ParseFilter(expr string) (*Filter, error) {
+	p := &filterParser{lex: newFilterLexer(expr)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+	return &Filter{expr: expr, root: root}, nil
+}
+
+func (f *Filter) String() string {
+	return f.expr
+}
+
+// Bind resolves the filter's fields against schema and converts its literals
+// to the matching field types.
+func (f *Filter) Bind(schema *arrow.Schema) error {
+	return f.root.bind(schema)
+}
+
+// Apply returns the rows of record that satisfy the filter. The filter must
+// have been bound to record's schema.
+func (f *Filter) Apply(ctx context.Context, record arrow.Record) (arrow.Record, error) {
+	mask, err := f.root.eval(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	defer mask.Release()
+
+	maskArr := mask.(*compute.ArrayDatum).MakeArray()
+	defer maskArr.Release()
+	return compute.FilterRecordBatch(ctx, record, maskArr, compute.DefaultFilterOptions())
+}
+
+// filterNode is a node of the filter expression tree. eval returns a boolean
+// array datum with one entry per row.
+type filterNode interface {
+	bind(schema *arrow.Schema) error
+	eval(ctx context.Context, record arrow.Record) (compute.Datum, error)
+}
+
+type logicalNode struct {
+	fn          string
+	left, right filterNode
+}
+
+func (n *logicalNode) bind(schema *arrow.Schema) error {
+	if err := n.left.bind(schema); err != nil {
+		return err
+	}
+	return n.right.bind(schema)
+}
+
+func (n *logicalNode) eval(ctx context.Context, record arrow.Record) (compute.Datum, error) {
+	left, err := n.left.eval(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	defer left.Release()
+	right, err := n.right.eval(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	defer right.Release()
+	return compute.CallFunction(ctx, n.fn, nil, left, right)
+}
+
+type notNode struct {
+	inner filterNode
+}
+
+func (n *notNode) bind(schema *arrow.Schema) error {
+	return n.inner.bind(schema)
+}
+
+func (n *notNode) eval(ctx context.Context, record arrow.Record) (compute.Datum, error) {
+	inner, err := n.inner.eval(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	defer inner.Release()
+	return compute.CallFunction(ctx, "not", nil, inner)
+}
+
+// operand is either a field reference or a literal.
+type operand struct {
+	field string
+	index int
+
+	literal *token
+	value   scalar.Scalar
+}
+
+func (o *operand) isField() bool {
+	return o.literal == nil
+}
+
+func (o *operand) bindField(schema *arrow.Schema) (arrow.DataType, error) {
+	indices := schema.FieldIndices(o.field)
+	if len(indices) == 0 {
+		return nil, fmt.Errorf("%w: unknown field %q in filter", ErrInvalidQuery, o.field)
+	}
+	o.index = indices[0]
+	return schema.Field(o.index).Type, nil
+}
+
+func (o *operand) bindLiteral(dt arrow.DataType) error {
+	var err error
+	switch o.literal.kind {
+	case tokTrue, tokFalse:
+		if dt.ID() != arrow.BOOL {
+			return fmt.Errorf("%w: cannot compare %s with a boolean", ErrInvalidQuery, dt)
+		}
+		o.value = scalar.NewBooleanScalar(o.literal.kind == tokTrue)
+	case tokNumber, tokString:
+		o.value, err = scalar.ParseScalar(dt, o.literal.text)
+		if err != nil {
+			return fmt.Errorf("%w: invalid %s literal %s: %v", ErrInvalidQuery, dt, o.literal, err)
+		}
+	}
+	return nil
+}
+
+// datum wraps the operand's value without taking ownership; the record owns
+// its columns and the operand owns its literal.
+func (o *operand) datum(record arrow.Record) compute.Datum {
+	if o.isField() {
+		return compute.NewDatumWithoutOwning(record.Column(o.index))
+	}
+	return compute.NewDatumWithoutOwning(o.value)
+}
+
+type compareNode struct {
+	fn          string
+	left, right *operand
+}
+
+func (n *compareNode) bind(schema *arrow.Schema) error {
+	switch {
+	case n.left.isField() && n.right.isField():
+		lt, err := n.left.bindField(schema)
+		if err != nil {
+			return err
+		}
+		rt, err := n.right.bindField(schema)
+		if err != nil {
+			return err
+		}
+		if !arrow.TypeEqual(lt, rt) {
+			return fmt.Errorf("%w: cannot compare %q (%s) with %q (%s)", ErrInvalidQuery, n.left.field, lt, n.right.field, rt)
+		}
+		return nil
+	case n.left.isField():
+		dt, err := n.left.bindField(schema)
+		if err != nil {
+			return err
+		}
+		return n.right.bindLiteral(dt)
+	case n.right.isField():
+		dt, err := n.right.bindField(schema)
+		if err != nil {
+			return err
+		}
+		return n.left.bindLiteral(dt)
+	default:
+		return fmt.Errorf("%w: comparison between two literals", ErrInvalidQuery)
+	}
+}
+
+func (n *compareNode) eval(ctx context.Context, record arrow.Record) (compute.Datum, error) {
+	return compute.CallFunction(ctx, n.fn, nil, n.left.datum(record), n.right.datum(record))
+}
+
+// inNode is evaluated as a chain of equality comparisons joined with OR.
+type inNode struct {
+	field  *operand
+	values []*operand
+}
+
+func (n *inNode) bind(schema *arrow.Schema) error {
+	dt, err := n.field.bindField(schema)
+	if err != nil {
+		return err
+	}
+	for _, v := range n.values {
+		if err := v.bindLiteral(dt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *inNode) eval(ctx context.Context, record arrow.Record) (compute.Datum, error) {
+	var result compute.Datum
+	for _, v := range n.values {
+		eq, err := compute.CallFunction(ctx, "equal", nil, n.field.datum(record), v.datum(record))
+		if err != nil {
+			if result != nil {
+				result.Release()
+			}
+			return nil, err
+		}
+		if result == nil {
+			result = eq
+			continue
+		}
+		combined, err := compute.CallFunction(ctx, "or_kleene", nil, result, eq)
+		result.Release()
+		eq.Release()
+		if err != nil {
+			return nil, err
+		}
+		result = combined
+	}
+	return result, nil
+}
+
+type nullNode struct {
+	field *operand
+	fn    string
+}
+
+func (n *nullNode) bind(schema *arrow.Schema) error {
+	_, err := n.field.bindField(schema)
+	return err
+}
+
+func (n *nullNode) eval(ctx context.Context, record arrow.Record) (compute.Datum, error) {
+	return compute.CallFunction(ctx, n.fn, nil, n.field.datum(record))
+}
+
+// boolFieldNode selects rows where a boolean field is true.
+type boolFieldNode struct {
+	field *operand
+}
+
+func (n *boolFieldNode) bind(schema *arrow.Schema) error {
+	dt, err := n.field.bindField(schema)
+	if err != nil {
+		return err
+	}
+	if dt.ID() != arrow.BOOL {
+		return fmt.Errorf("%w: field %q is %s, not boolean", ErrInvalidQuery, n.field.field, dt)
+	}
+	return nil
+}
+
+func (n *boolFieldNode) eval(ctx context.Context, record arrow.Record) (compute.Datum, error) {
+	return compute.NewDatum(record.Column(n.field.index)), nil
+}
+
+type filterParser struct {
+	lex *filterLexer
+	tok token
+}
+
+func (p *filterParser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *filterParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: filter position %d: %s", ErrInvalidQuery, p.tok.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *filterParser) expect(kind tokenKind) error {
+	if p.tok.kind != kind {
+		return p.errorf("expected %s, got %s", kind, p.tok)
+	}
+	return p.advance()
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokOr {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{fn: "or_kleene", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokAnd {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{fn: "and_kleene", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filterNode, error) {
+	if p.tok.kind == tokNot {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{inner: inner}, nil
+	}
+	return p.parsePrimary()
+}
+
+var compareFuncs = map[string]string{
+	"=":  "equal",
+	"==": "equal",
+	"!=": "not_equal",
+	"<>": "not_equal",
+	"<":  "less",
+	"<=": "less_equal",
+	">":  "greater",
+	">=": "greater_equal",
+}
+
+func (p *filterParser) parsePrimary() (filterNode, error) {
+	if p.tok.kind == tokLParen {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokRParen); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	switch p.tok.kind {
+	case tokOp:
+		fn := compareFuncs[p.tok.text]
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return &compareNode{fn: fn, left: left, right: right}, nil
+
+	case tokIn, tokNot:
+		negate := p.tok.kind == tokNot
+		if negate {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if p.tok.kind != tokIn {
+				return nil, p.errorf("expected IN after NOT, got %s", p.tok)
+			}
+		}
+		if !left.isField() {
+			return nil, p.errorf("IN requires a field on the left")
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		var node filterNode = &inNode{field: left, values: values}
+		if negate {
+			node = &notNode{inner: node}
+		}
+		return node, nil
+
+	case tokIs:
+		if !left.isField() {
+			return nil, p.errorf("IS NULL requires a field on the left")
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		fn := "is_null"
+		if p.tok.kind == tokNot {
+			fn = "is_not_null"
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.expect(tokNull); err != nil {
+			return nil, err
+		}
+		return &nullNode{field: left, fn: fn}, nil
+
+	default:
+		if !left.isField() {
+			return nil, p.errorf("expected a predicate, got literal %s", left.literal)
+		}
+		return &boolFieldNode{field: left}, nil
+	}
+}
+
+func (p *filterParser) parseOperand() (*operand, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokIdent:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return &operand{field: tok.text}, nil
+	case tokString, tokNumber, tokTrue, tokFalse:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return &operand{literal: &tok}, nil
+	default:
+		return nil, p.errorf("expected a field or literal, got %s", tok)
+	}
+}
+
+func (p *filterParser) parseList() ([]*operand, error) {
+	if err := p.expect(tokLParen); err != nil {
+		return nil, err
+	}
+	var values []*operand
+	for {
+		v, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if v.isField() {
+			return nil, p.errorf("IN lists may only contain literals")
+		}
+		values = append(values, v)
+		if p.tok.kind != tokComma {
+			break
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expect(tokRParen); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+	tokAnd
+	tokOr
+	tokNot
+	tokIn
+	tokIs
+	tokNull
+	tokTrue
+	tokFalse
+)
+
+var tokenNames = map[tokenKind]string{
+	tokEOF:    "end of filter",
+	tokIdent:  "field",
+	tokString: "string",
+	tokNumber: "number",
+	tokOp:     "comparison",
+	tokLParen: "'('",
+	tokRParen: "')'",
+	tokComma:  "','",
+	tokAnd:    "AND",
+	tokOr:     "OR",
+	tokNot:    "NOT",
+	tokIn:     "IN",
+	tokIs:     "IS",
+	tokNull:   "NULL",
+	tokTrue:   "TRUE",
+	tokFalse:  "FALSE",
+}
+
+func (k tokenKind) String() string {
+	return tokenNames[k]
+}
+
+var keywords = map[string]tokenKind{
+	"AND":   tokAnd,
+	"OR":    tokOr,
+	"NOT":   tokNot,
+	"IN":    tokIn,
+	"IS":    tokIs,
+	"NULL":  tokNull,
+	"TRUE":  tokTrue,
+	"FALSE": tokFalse,
+}
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return t.kind.String()
+	case tokString:
+		return fmt.Sprintf("'%s'", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+type filterLexer struct {
+	src []rune
+	pos int
+}
+
+func newFilterLexer(src string) *filterLexer {
+	return &filterLexer{src: []rune(src)}
+}
+
+func (l *filterLexer) errorf(pos int, format string, args ...interface{}) error {
+	return fmt.Errorf("%w: filter position %d: %s", ErrInvalidQuery, pos, fmt.Sprintf(format, args...))
+}
+
+func (l *filterLexer) next() (token, error) {
+	for l.pos < len(l.src) && unicode.IsSpace(l.src[l.pos]) {
+		l.pos++
+	}
+	start := l.pos
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF, pos: start}, nil
+	}
+
+	c := l.src[l.pos]
+	switch {
+	case c == '(':
+		l.pos++
+		return token{kind: tokLParen, text: "(", pos: start}, nil
+	case c == ')':
+		l.pos++
+		return token{kind: tokRParen, text: ")", pos: start}, nil
+	case c == ',':
+		l.pos++
+		return token{kind: tokComma, text: ",", pos: start}, nil
+	case strings.ContainsRune("=!<>", c):
+		l.pos++
+		if l.pos < len(l.src) {
+			if two := string(l.src[start : l.pos+1]); compareFuncs[two] != "" {
+				l.pos++
+				return token{kind: tokOp, text: two, pos: start}, nil
+			}
+		}
+		if one := string(c); compareFuncs[one] != "" {
+			return token{kind: tokOp, text: one, pos: start}, nil
+		}
+		return token{}, l.errorf(start, "unexpected %q", c)
+	case c == '\'' || c == '"':
+		text, err := l.quoted(c)
+		if err != nil {
+			return token{}, err
+		}
+		kind := tokString
+		if c == '"' {
+			kind = tokIdent
+		}
+		return token{kind: kind, text: text, pos: start}, nil
+	case unicode.IsDigit(c) || c == '-' || c == '+' || c == '.':
+		l.pos++
+		for l.pos < len(l.src) && (unicode.IsDigit(l.src[l.pos]) || strings.ContainsRune(".eE+-", l.src[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokNumber, text: string(l.src[start:l.pos]), pos: start}, nil
+	case unicode.IsLetter(c) || c == '_':
+		for l.pos < len(l.src) && (unicode.IsLetter(l.src[l.pos]) || unicode.IsDigit(l.src[l.pos]) || l.src[l.pos] == '_') {
+			l.pos++
+		}
+		text := string(l.src[start:l.pos])
+		if kind, ok := keywords[strings.ToUpper(text)]; ok {
+			return token{kind: kind, text: text, pos: start}, nil
+		}
+		return token{kind: tokIdent, text: text, pos: start}, nil
+	default:
+		return token{}, l.errorf(start, "unexpected %q", c)
+	}
+}
+
+// quoted reads a string delimited by quote, where a doubled quote stands for
+// the quote character itself.
+func (l *filterLexer) quoted(quote rune) (string, error) {
+	start := l.pos
+	l.pos++
+	var b strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		l.pos++
+		if c != quote {
+			b.WriteRune(c)
+			continue
+		}
+		if l.pos < len(l.src) && l.src[l.pos] == quote {
+			b.WriteRune(quote)
+			l.pos++
+			continue
+		}
+		return b.String(), nil
+	}
+	return "", l.errorf(start, "unterminated quoted string")
+}
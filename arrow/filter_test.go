@@ -0,0 +1,192 @@
+package arrow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	arrow "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+var filterTestSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+	{Name: "category", Type: arrow.BinaryTypes.String},
+	{Name: "value", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "flag", Type: arrow.FixedWidthTypes.Boolean},
+	{Name: "note", Type: arrow.BinaryTypes.String, Nullable: true},
+	{Name: "odd name", Type: arrow.PrimitiveTypes.Int64},
+}, nil)
+
+// filterTestRecord returns the rows
+//
+//	id category value flag  note   "odd name"
+//	1  A        10    true  x      1
+//	2  B        20    false null   2
+//	3  C        30    true  it's   3
+//	4  A        40    false null   4
+//	5  B        50    true  y      5
+func filterTestRecord(mem memory.Allocator) arrow.Record {
+	b := array.NewRecordBuilder(mem, filterTestSchema)
+	defer b.Release()
+	b.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 3, 4, 5}, nil)
+	b.Field(1).(*array.StringBuilder).AppendValues([]string{"A", "B", "C", "A", "B"}, nil)
+	b.Field(2).(*array.Float64Builder).AppendValues([]float64{10, 20, 30, 40, 50}, nil)
+	b.Field(3).(*array.BooleanBuilder).AppendValues([]bool{true, false, true, false, true}, nil)
+	b.Field(4).(*array.StringBuilder).AppendValues([]string{"x", "", "it's", "", "y"}, []bool{true, false, true, false, true})
+	b.Field(5).(*array.Int64Builder).AppendValues([]int64{1, 2, 3, 4, 5}, nil)
+	return b.NewRecord()
+}
+
+// recordIDs returns the id column of record, the first column of records
+// built by filterTestRecord.
+func recordIDs(record arrow.Record) []int64 {
+	ids := []int64{}
+	col := record.Column(0).(*array.Int64)
+	for i := 0; i < col.Len(); i++ {
+		ids = append(ids, col.Value(i))
+	}
+	return ids
+}
+
+func TestFilterApply(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []int64
+	}{
+		{"id = 3", []int64{3}},
+		{"id == 3", []int64{3}},
+		{"id != 3", []int64{1, 2, 4, 5}},
+		{"id <> 3", []int64{1, 2, 4, 5}},
+		{"value < 30", []int64{1, 2}},
+		{"value <= 30", []int64{1, 2, 3}},
+		{"value > 30", []int64{4, 5}},
+		{"value >= 30", []int64{3, 4, 5}},
+		{"30 < value", []int64{4, 5}},
+		{"value > -1.5e1", []int64{1, 2, 3, 4, 5}},
+		{"id = \"odd name\"", []int64{1, 2, 3, 4, 5}},
+		{"category = 'A'", []int64{1, 4}},
+
+		// AND binds tighter than OR, and NOT tighter than both
+		{"id = 1 OR id = 2 AND category = 'A'", []int64{1}},
+		{"(id = 1 OR id = 2) AND category = 'B'", []int64{2}},
+		{"NOT id = 1 AND category = 'A'", []int64{4}},
+		{"NOT (id = 1 AND category = 'A')", []int64{2, 3, 4, 5}},
+		{"NOT NOT flag", []int64{1, 3, 5}},
+		{"id = 1 or id = 5 and not flag", []int64{1}},
+
+		{"category IN ('A', 'C')", []int64{1, 3, 4}},
+		{"category NOT IN ('A', 'C')", []int64{2, 5}},
+		{"id IN (2)", []int64{2}},
+		{"value in (10, 50.0)", []int64{1, 5}},
+
+		{"note IS NULL", []int64{2, 4}},
+		{"note IS NOT NULL", []int64{1, 3, 5}},
+		// Comparisons with NULL drop the row either way
+		{"note = 'x'", []int64{1}},
+		{"note != 'x'", []int64{3, 5}},
+
+		{"flag", []int64{1, 3, 5}},
+		{"NOT flag", []int64{2, 4}},
+		{"flag = TRUE", []int64{1, 3, 5}},
+		{"flag = false", []int64{2, 4}},
+		{"flag AND category = 'B'", []int64{5}},
+
+		{"note = 'it''s'", []int64{3}},
+		{"\"odd name\" >= 4", []int64{4, 5}},
+		{"  id   =   2  ", []int64{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+			record := filterTestRecord(mem)
+			defer record.Release()
+
+			f, err := ParseFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseFilter: %v", err)
+			}
+			if f.String() != tt.expr {
+				t.Errorf("String() = %q, want %q", f.String(), tt.expr)
+			}
+			if err := f.Bind(record.Schema()); err != nil {
+				t.Fatalf("Bind: %v", err)
+			}
+			filtered, err := f.Apply(context.Background(), record)
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			defer filtered.Release()
+			if got := recordIDs(filtered); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ids = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		// bind is set for errors found when binding rather than parsing
+		bind bool
+	}{
+		{expr: ""},
+		{expr: "id ="},
+		{expr: "id = 1 AND"},
+		{expr: "(id = 1"},
+		{expr: "id = 1)"},
+		{expr: "id = 1 id = 2"},
+		{expr: "id ! 1"},
+		{expr: "id # 1"},
+		{expr: "category = 'A"},
+		{expr: "\"odd name = 1"},
+		{expr: "category IN ()"},
+		{expr: "category IN ('A' 'B')"},
+		{expr: "category IN ('A', id)"},
+		{expr: "category NOT 'A'"},
+		{expr: "'A' IN ('A')"},
+		{expr: "'A' IS NULL"},
+		{expr: "note IS 'x'"},
+		{expr: "note IS NOT"},
+		{expr: "42"},
+		{expr: "AND"},
+
+		{expr: "missing = 1", bind: true},
+		{expr: "missing", bind: true},
+		{expr: "missing IS NULL", bind: true},
+		{expr: "missing IN (1)", bind: true},
+		{expr: "id", bind: true},
+		{expr: "id = 'abc'", bind: true},
+		{expr: "id IN (1, 'abc')", bind: true},
+		{expr: "id = TRUE", bind: true},
+		{expr: "1 = 1", bind: true},
+		{expr: "id = category", bind: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := ParseFilter(tt.expr)
+			if !tt.bind {
+				if err == nil {
+					t.Fatal("ParseFilter succeeded, want an error")
+				}
+				if !errors.Is(err, ErrInvalidQuery) {
+					t.Errorf("error %v does not wrap ErrInvalidQuery", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFilter: %v", err)
+			}
+			err = f.Bind(filterTestSchema)
+			if err == nil {
+				t.Fatal("Bind succeeded, want an error")
+			}
+			if !errors.Is(err, ErrInvalidQuery) {
+				t.Errorf("error %v does not wrap ErrInvalidQuery", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,575 @@
+package arrow
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	arrow "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"gopkg.in/yaml.v3"
+)
+
+// Column types accepted in ColumnSpec.Type.
+const (
+	TypeInt32     = "int32"
+	TypeInt64     = "int64"
+	TypeFloat32   = "float32"
+	TypeFloat64   = "float64"
+	TypeBool      = "bool"
+	TypeString    = "string"
+	TypeTimestamp = "timestamp"
+	TypeStruct    = "struct"
+	TypeList      = "list"
+)
+
+// Distributions accepted in ColumnSpec.Distribution.
+const (
+	// DistUniform draws numbers evenly from [Min, Max), or [Min, Max] for
+	// integers, and strings of random letters and digits.
+	DistUniform = "uniform"
+	// DistNormal draws numbers from a normal distribution of Mean and StdDev.
+	DistNormal = "normal"
+	// DistZipf draws Min plus a Zipf-distributed integer in [0, Max-Min] with
+	// parameters S > 1 and V >= 1: small values are the most frequent.
+	DistZipf = "zipf"
+	// DistSequence counts from Start in increments of Step, one per row.
+	DistSequence = "sequence"
+	// DistEnum picks one of Values, in proportion to Weights if given.
+	DistEnum = "enum"
+)
+
+// GeneratorSpec declares the columns of a synthetic dataset and how their
+// values are distributed. In YAML:
+//
+//	seed: 42
+//	columns:
+//	  - {name: id, type: int64, distribution: sequence}
+//	  - {name: price, type: float64, distribution: normal, mean: 100, stddev: 15, null_fraction: 0.01}
+//	  - {name: product, type: int32, distribution: zipf, s: 1.2, max: 1000}
+//	  - {name: status, type: string, values: [ok, retry, failed], weights: [90, 8, 2]}
+//	  - {name: event_time, type: timestamp, from: "2024-01-01T00:00:00Z", interval: 1s, jitter: 200ms}
+//	  - name: tags
+//	    type: list
+//	    max_items: 3
+//	    items: {type: string, min_length: 3, max_length: 8}
+type GeneratorSpec struct {
+	// Seed makes the generated data reproducible: the same spec, seed, row
+	// count and batch size always produce the same batches, in any process.
+	// Timestamp columns that do not set From then start at the Unix epoch.
+	// Without a seed every read produces different data.
+	Seed    *int64       `json:"seed,omitempty" yaml:"seed,omitempty"`
+	Columns []ColumnSpec `json:"columns" yaml:"columns"`
+}
+
+// ColumnSpec declares one column. Fields that do not apply to the column's
+// type and distribution are ignored.
+type ColumnSpec struct {
+	// Name is required for columns and struct fields, and ignored for list
+	// items.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	Type string `json:"type" yaml:"type"`
+	// Distribution defaults to DistUniform, or DistEnum when Values is set.
+	// Timestamps always follow From, Interval and Jitter.
+	Distribution string `json:"distribution,omitempty" yaml:"distribution,omitempty"`
+	// NullFraction is the probability, between 0 and 1, that a value is null.
+	NullFraction float64 `json:"null_fraction,omitempty" yaml:"null_fraction,omitempty"`
+
+	// Min and Max bound DistUniform and DistZipf. Both zero means 0 and 100.
+	Min float64 `json:"min,omitempty" yaml:"min,omitempty"`
+	Max float64 `json:"max,omitempty" yaml:"max,omitempty"`
+	// Mean and StdDev parametrize DistNormal.
+	Mean   float64 `json:"mean,omitempty" yaml:"mean,omitempty"`
+	StdDev float64 `json:"stddev,omitempty" yaml:"stddev,omitempty"`
+	// S and V parametrize DistZipf. V defaults to 1.
+	S float64 `json:"s,omitempty" yaml:"s,omitempty"`
+	V float64 `json:"v,omitempty" yaml:"v,omitempty"`
+	// Start and Step parametrize DistSequence. Step defaults to 1.
+	Start float64 `json:"start,omitempty" yaml:"start,omitempty"`
+	Step  float64 `json:"step,omitempty" yaml:"step,omitempty"`
+
+	// Values and Weights define DistEnum for strings.
+	Values  []string  `json:"values,omitempty" yaml:"values,omitempty"`
+	Weights []float64 `json:"weights,omitempty" yaml:"weights,omitempty"`
+	// MinLength and MaxLength bound the length of uniform strings. Both
+	// default to 8.
+	MinLength int `json:"min_length,omitempty" yaml:"min_length,omitempty"`
+	MaxLength int `json:"max_length,omitempty" yaml:"max_length,omitempty"`
+
+	// Probability is the chance of a bool being true. Defaults to 0.5.
+	Probability *float64 `json:"probability,omitempty" yaml:"probability,omitempty"`
+
+	// From is the RFC 3339 time of the first row of a timestamp column,
+	// defaulting to the time of the read, or to the Unix epoch if the spec
+	// has a seed. Each row is Interval (default
+	// "1s") after the previous one, moved by a uniform random offset of up
+	// to Jitter in either direction. Unit is s, ms (the default), us or ns.
+	From     string `json:"from,omitempty" yaml:"from,omitempty"`
+	Interval string `json:"interval,omitempty" yaml:"interval,omitempty"`
+	Jitter   string `json:"jitter,omitempty" yaml:"jitter,omitempty"`
+	Unit     string `json:"unit,omitempty" yaml:"unit,omitempty"`
+
+	// Fields are the children of a struct.
+	Fields []ColumnSpec `json:"fields,omitempty" yaml:"fields,omitempty"`
+	// Items describes the elements of a list, which holds between MinItems
+	// and MaxItems of them.
+	Items    *ColumnSpec `json:"items,omitempty" yaml:"items,omitempty"`
+	MinItems int         `json:"min_items,omitempty" yaml:"min_items,omitempty"`
+	MaxItems int         `json:"max_items,omitempty" yaml:"max_items,omitempty"`
+}
+
+// DefaultGeneratorSpec describes the dataset DemoArrowService has always
+// produced: a sequential id, a timestamp per second, a uniform value, one of
+// five categories and a flag that is true 70% of the time.
+func DefaultGeneratorSpec() GeneratorSpec {
+	valid := 0.7
+	return GeneratorSpec{Columns: []ColumnSpec{
+		{Name: "id", Type: TypeInt64, Distribution: DistSequence},
+		{Name: "timestamp", Type: TypeTimestamp},
+		{Name: "value", Type: TypeFloat64, Min: 0, Max: 100},
+		{Name: "category", Type: TypeString, Values: []string{"A", "B", "C", "D", "E"}},
+		{Name: "is_valid", Type: TypeBool, Probability: &valid},
+	}}
+}
+
+// LoadGeneratorSpec reads a spec from a JSON (.json) or YAML (.yaml, .yml)
+// file and validates it.
+func LoadGeneratorSpec(path string) (GeneratorSpec, error) {
+	var spec GeneratorSpec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return spec, err
+	}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&spec)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err = dec.Decode(&spec); errors.Is(err, io.EOF) {
+			err = nil
+		}
+	default:
+		return spec, fmt.Errorf("schema spec %s: unsupported format %q (want .json, .yaml or .yml)", path, ext)
+	}
+	if err != nil {
+		return spec, fmt.Errorf("parse schema spec %s: %w", path, err)
+	}
+	if err := spec.Validate(); err != nil {
+		return spec, fmt.Errorf("schema spec %s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// Validate reports the first problem with the spec.
+func (s GeneratorSpec) Validate() error {
+	if len(s.Columns) == 0 {
+		return errors.New("no columns")
+	}
+	names := make(map[string]bool)
+	for _, c := range s.Columns {
+		if names[c.Name] {
+			return fmt.Errorf("duplicate column %q", c.Name)
+		}
+		names[c.Name] = true
+		if err := c.validate(c.Name, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c ColumnSpec) validate(path string, named bool) error {
+	fail := func(format string, args ...any) error {
+		return fmt.Errorf("column %q: %s", path, fmt.Sprintf(format, args...))
+	}
+	if named && c.Name == "" {
+		return fail("name is required")
+	}
+	if c.NullFraction < 0 || c.NullFraction > 1 {
+		return fail("null_fraction must be between 0 and 1, got %g", c.NullFraction)
+	}
+
+	switch c.Type {
+	case TypeInt32, TypeInt64, TypeFloat32, TypeFloat64:
+		integer := c.Type == TypeInt32 || c.Type == TypeInt64
+		lo, hi := c.bounds()
+		switch dist := c.distribution(); dist {
+		case DistUniform:
+			if integer && math.Floor(hi) < math.Ceil(lo) {
+				return fail("uniform needs an integer between min %g and max %g", lo, hi)
+			}
+			if !integer && hi <= lo {
+				return fail("uniform needs max > min, got min %g and max %g", lo, hi)
+			}
+		case DistNormal:
+			if c.StdDev < 0 {
+				return fail("stddev must not be negative, got %g", c.StdDev)
+			}
+		case DistZipf:
+			if c.S <= 1 {
+				return fail("zipf needs s > 1, got %g", c.S)
+			}
+			if c.V != 0 && c.V < 1 {
+				return fail("zipf needs v >= 1, got %g", c.V)
+			}
+			if hi < lo+1 {
+				return fail("zipf needs max >= min+1, got min %g and max %g", lo, hi)
+			}
+		case DistSequence:
+		default:
+			return fail("distribution %q does not apply to %s (want uniform, normal, zipf or sequence)", dist, c.Type)
+		}
+	case TypeString:
+		switch dist := c.distribution(); dist {
+		case DistEnum:
+			if len(c.Values) == 0 {
+				return fail("enum needs values")
+			}
+			if len(c.Weights) > 0 {
+				if len(c.Weights) != len(c.Values) {
+					return fail("%d weights for %d values", len(c.Weights), len(c.Values))
+				}
+				var total float64
+				for _, w := range c.Weights {
+					if w < 0 {
+						return fail("weights must not be negative")
+					}
+					total += w
+				}
+				if total == 0 {
+					return fail("weights must not all be zero")
+				}
+			}
+		case DistUniform:
+			if c.MinLength < 0 || c.MaxLength < 0 {
+				return fail("string lengths must not be negative")
+			}
+			if c.MaxLength > 0 && c.MaxLength < c.minLength() {
+				return fail("max_length %d is less than min_length %d", c.MaxLength, c.minLength())
+			}
+		default:
+			return fail("distribution %q does not apply to strings (want uniform or enum)", dist)
+		}
+	case TypeBool:
+		if p := c.Probability; p != nil && (*p < 0 || *p > 1) {
+			return fail("probability must be between 0 and 1, got %g", *p)
+		}
+	case TypeTimestamp:
+		if c.From != "" {
+			if _, err := time.Parse(time.RFC3339Nano, c.From); err != nil {
+				return fail("from: %v", err)
+			}
+		}
+		if _, err := c.duration(c.Interval, time.Second); err != nil {
+			return fail("interval: %v", err)
+		}
+		if jitter, err := c.duration(c.Jitter, 0); err != nil || jitter < 0 {
+			return fail("jitter must be a non-negative duration, got %q", c.Jitter)
+		}
+		if _, err := c.timeUnit(); err != nil {
+			return fail("%v", err)
+		}
+	case TypeStruct:
+		if len(c.Fields) == 0 {
+			return fail("struct needs fields")
+		}
+		names := make(map[string]bool)
+		for _, f := range c.Fields {
+			if names[f.Name] {
+				return fail("duplicate field %q", f.Name)
+			}
+			names[f.Name] = true
+			if err := f.validate(path+"."+f.Name, true); err != nil {
+				return err
+			}
+		}
+	case TypeList:
+		if c.Items == nil {
+			return fail("list needs items")
+		}
+		if c.MinItems < 0 || c.MaxItems < c.MinItems {
+			return fail("list needs 0 <= min_items <= max_items, got %d and %d", c.MinItems, c.MaxItems)
+		}
+		if err := c.Items.validate(path+"[]", false); err != nil {
+			return err
+		}
+	default:
+		return fail("unknown type %q", c.Type)
+	}
+	return nil
+}
+
+func (c ColumnSpec) distribution() string {
+	if c.Distribution != "" {
+		return c.Distribution
+	}
+	if len(c.Values) > 0 {
+		return DistEnum
+	}
+	return DistUniform
+}
+
+func (c ColumnSpec) bounds() (lo, hi float64) {
+	if c.Min == 0 && c.Max == 0 {
+		return 0, 100
+	}
+	return c.Min, c.Max
+}
+
+func (c ColumnSpec) minLength() int {
+	if c.MinLength == 0 && c.MaxLength == 0 {
+		return 8
+	}
+	return c.MinLength
+}
+
+func (c ColumnSpec) maxLength() int {
+	if c.MaxLength == 0 {
+		return c.minLength()
+	}
+	return c.MaxLength
+}
+
+func (c ColumnSpec) duration(s string, fallback time.Duration) (time.Duration, error) {
+	if s == "" {
+		return fallback, nil
+	}
+	return time.ParseDuration(s)
+}
+
+func (c ColumnSpec) timeUnit() (arrow.TimeUnit, error) {
+	switch c.Unit {
+	case "s":
+		return arrow.Second, nil
+	case "", "ms":
+		return arrow.Millisecond, nil
+	case "us":
+		return arrow.Microsecond, nil
+	case "ns":
+		return arrow.Nanosecond, nil
+	}
+	return 0, fmt.Errorf("unknown unit %q (want s, ms, us or ns)", c.Unit)
+}
+
+// Schema returns the Arrow schema of the generated data. The spec must be
+// valid. Columns are nullable when their null fraction is positive.
+func (s GeneratorSpec) Schema() *arrow.Schema {
+	fields := make([]arrow.Field, len(s.Columns))
+	for i, c := range s.Columns {
+		fields[i] = c.field()
+	}
+	return arrow.NewSchema(fields, nil)
+}
+
+func (c ColumnSpec) field() arrow.Field {
+	return arrow.Field{Name: c.Name, Type: c.dataType(), Nullable: c.NullFraction > 0}
+}
+
+func (c ColumnSpec) dataType() arrow.DataType {
+	switch c.Type {
+	case TypeInt32:
+		return arrow.PrimitiveTypes.Int32
+	case TypeInt64:
+		return arrow.PrimitiveTypes.Int64
+	case TypeFloat32:
+		return arrow.PrimitiveTypes.Float32
+	case TypeFloat64:
+		return arrow.PrimitiveTypes.Float64
+	case TypeBool:
+		return arrow.FixedWidthTypes.Boolean
+	case TypeString:
+		return arrow.BinaryTypes.String
+	case TypeTimestamp:
+		unit, _ := c.timeUnit()
+		return &arrow.TimestampType{Unit: unit}
+	case TypeStruct:
+		fields := make([]arrow.Field, len(c.Fields))
+		for i, f := range c.Fields {
+			fields[i] = f.field()
+		}
+		return arrow.StructOf(fields...)
+	case TypeList:
+		items := c.Items.field()
+		items.Name = "item"
+		return arrow.ListOfField(items)
+	}
+	panic("arrow: invalid column type " + c.Type)
+}
+
+// columnGenerator appends the value of a row to the builder it was bound to.
+type columnGenerator func(row int64)
+
+// generator binds the columns of s to the fields of a record builder. Values
+// are drawn from r, and timestamps start at from unless the spec sets them.
+func (s GeneratorSpec) generator(b *array.RecordBuilder, r *rand.Rand, from time.Time) columnGenerator {
+	columns := make([]columnGenerator, len(s.Columns))
+	for i, c := range s.Columns {
+		columns[i] = c.generator(b.Field(i), r, from)
+	}
+	return func(row int64) {
+		for _, gen := range columns {
+			gen(row)
+		}
+	}
+}
+
+func (c ColumnSpec) generator(b array.Builder, r *rand.Rand, from time.Time) columnGenerator {
+	gen := c.valueGenerator(b, r, from)
+	if c.NullFraction <= 0 {
+		return gen
+	}
+	return func(row int64) {
+		if r.Float64() < c.NullFraction {
+			b.AppendNull()
+			return
+		}
+		gen(row)
+	}
+}
+
+func (c ColumnSpec) valueGenerator(b array.Builder, r *rand.Rand, from time.Time) columnGenerator {
+	switch b := b.(type) {
+	case *array.Int32Builder:
+		next := c.numbers(r, true)
+		return func(row int64) { b.Append(int32(next(row))) }
+	case *array.Int64Builder:
+		next := c.numbers(r, true)
+		return func(row int64) { b.Append(int64(next(row))) }
+	case *array.Float32Builder:
+		next := c.numbers(r, false)
+		return func(row int64) { b.Append(float32(next(row))) }
+	case *array.Float64Builder:
+		next := c.numbers(r, false)
+		return func(row int64) { b.Append(next(row)) }
+	case *array.BooleanBuilder:
+		p := 0.5
+		if c.Probability != nil {
+			p = *c.Probability
+		}
+		return func(int64) { b.Append(r.Float64() < p) }
+	case *array.StringBuilder:
+		next := c.strings(r)
+		return func(int64) { b.Append(next()) }
+	case *array.TimestampBuilder:
+		return c.timestamps(b, r, from)
+	case *array.StructBuilder:
+		fields := make([]columnGenerator, len(c.Fields))
+		for i, f := range c.Fields {
+			fields[i] = f.generator(b.FieldBuilder(i), r, from)
+		}
+		return func(row int64) {
+			b.Append(true)
+			for _, gen := range fields {
+				gen(row)
+			}
+		}
+	case *array.ListBuilder:
+		items := c.Items.generator(b.ValueBuilder(), r, from)
+		return func(row int64) {
+			b.Append(true)
+			n := c.MinItems + r.Intn(c.MaxItems-c.MinItems+1)
+			for i := 0; i < n; i++ {
+				items(row)
+			}
+		}
+	}
+	panic(fmt.Sprintf("arrow: no generator for %T", b))
+}
+
+// numbers returns a source of values following the column's distribution.
+// Integer values are whole numbers.
+func (c ColumnSpec) numbers(r *rand.Rand, integer bool) func(row int64) float64 {
+	lo, hi := c.bounds()
+	switch c.distribution() {
+	case DistNormal:
+		return func(int64) float64 {
+			v := r.NormFloat64()*c.StdDev + c.Mean
+			if integer {
+				v = math.Round(v)
+			}
+			return v
+		}
+	case DistZipf:
+		v := c.V
+		if v == 0 {
+			v = 1
+		}
+		z := rand.NewZipf(r, c.S, v, uint64(hi-lo))
+		return func(int64) float64 { return lo + float64(z.Uint64()) }
+	case DistSequence:
+		step := c.Step
+		if step == 0 {
+			step = 1
+		}
+		return func(row int64) float64 { return c.Start + step*float64(row) }
+	}
+	if integer {
+		first, n := int64(math.Ceil(lo)), int64(math.Floor(hi))-int64(math.Ceil(lo))+1
+		return func(int64) float64 { return float64(first + r.Int63n(n)) }
+	}
+	return func(int64) float64 { return lo + r.Float64()*(hi-lo) }
+}
+
+const randomLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+func (c ColumnSpec) strings(r *rand.Rand) func() string {
+	if c.distribution() == DistEnum {
+		if len(c.Weights) == 0 {
+			return func() string { return c.Values[r.Intn(len(c.Values))] }
+		}
+		cumulative := make([]float64, len(c.Weights))
+		var total float64
+		for i, w := range c.Weights {
+			total += w
+			cumulative[i] = total
+		}
+		return func() string {
+			x := r.Float64() * total
+			for i, bound := range cumulative {
+				if x < bound {
+					return c.Values[i]
+				}
+			}
+			return c.Values[len(c.Values)-1]
+		}
+	}
+
+	minLen, maxLen := c.minLength(), c.maxLength()
+	buf := make([]byte, maxLen)
+	return func() string {
+		n := minLen + r.Intn(maxLen-minLen+1)
+		for i := range buf[:n] {
+			buf[i] = randomLetters[r.Intn(len(randomLetters))]
+		}
+		return string(buf[:n])
+	}
+}
+
+func (c ColumnSpec) timestamps(b *array.TimestampBuilder, r *rand.Rand, from time.Time) columnGenerator {
+	if c.From != "" {
+		from, _ = time.Parse(time.RFC3339Nano, c.From)
+	}
+	interval, _ := c.duration(c.Interval, time.Second)
+	jitter, _ := c.duration(c.Jitter, 0)
+	unit, _ := c.timeUnit()
+	perUnit := int64(unit.Multiplier())
+	start := from.UnixNano()
+	return func(row int64) {
+		ns := start + row*int64(interval)
+		if jitter > 0 {
+			ns += r.Int63n(2*int64(jitter)+1) - int64(jitter)
+		}
+		b.Append(arrow.Timestamp(ns / perUnit))
+	}
+}
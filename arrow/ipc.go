@@ -0,0 +1,194 @@
+package arrow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+
+	arrow "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// DefaultBatchSize is the maximum number of rows per record batch when no
+// batch size is configured. At the demo schema's ~28 bytes per row this keeps
+// each message well below gRPC's default 4 MB receive limit.
+const DefaultBatchSize = 64 * 1024
+
+// SerializeTimings breaks down the time spent serializing a record batch.
+type SerializeTimings struct {
+	// Total is the time spent serializing the batch.
+	Total time.Duration
+	// Encode is the part of Total spent building the IPC messages, as opposed
+	// to copying them into the payload. Body buffers are compressed while
+	// encoding, so with a codec other than CodecNone it is dominated by
+	// compression.
+	Encode time.Duration
+}
+
+// SerializeRecord encodes a single record batch as a self-contained Arrow IPC
+// stream, so each payload can be decoded on its own. The body buffers are
+// compressed with codec.
+func SerializeRecord(record arrow.Record, codec Codec) ([]byte, error) {
+	payload, _, err := SerializeRecordTimed(record, codec)
+	return payload, err
+}
+
+// SerializeRecordTimed is SerializeRecord that also reports where the time
+// went.
+func SerializeRecordTimed(record arrow.Record, codec Codec) ([]byte, SerializeTimings, error) {
+	start := time.Now()
+	pw := &streamPayloadWriter{}
+	opts := append([]ipc.Option{ipc.WithSchema(record.Schema())}, codec.IPCOptions()...)
+	writer := ipc.NewWriterWithPayloadWriter(pw, opts...)
+	if err := writer.Write(record); err != nil {
+		writer.Close()
+		return nil, SerializeTimings{}, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, SerializeTimings{}, err
+	}
+	total := time.Since(start)
+	return pw.buf.Bytes(), SerializeTimings{Total: total, Encode: total - pw.writing}, nil
+}
+
+// ipcContinuation marks the start of an encapsulated IPC message.
+const ipcContinuation = 0xFFFFFFFF
+
+// streamPayloadWriter writes IPC payloads in the streaming format, keeping
+// track of the time spent doing so.
+type streamPayloadWriter struct {
+	buf     bytes.Buffer
+	writing time.Duration
+}
+
+func (w *streamPayloadWriter) Start() error { return nil }
+
+// WritePayload writes an encapsulated message: the continuation marker, the
+// metadata length, the metadata padded to 8 bytes, then the body.
+func (w *streamPayloadWriter) WritePayload(p ipc.Payload) error {
+	start := time.Now()
+	defer func() { w.writing += time.Since(start) }()
+
+	meta := p.Meta()
+	defer meta.Release()
+	padded := (meta.Len() + 7) &^ 7
+
+	var prefix [8]byte
+	binary.LittleEndian.PutUint32(prefix[:4], ipcContinuation)
+	binary.LittleEndian.PutUint32(prefix[4:], uint32(padded))
+	w.buf.Write(prefix[:])
+	w.buf.Write(meta.Bytes())
+	w.buf.Write(make([]byte, padded-meta.Len()))
+	return p.SerializeBody(&w.buf)
+}
+
+// Close writes the end-of-stream marker.
+func (w *streamPayloadWriter) Close() error {
+	w.buf.Write(EndOfStream)
+	return nil
+}
+
+// FramingHeader is the response metadata key in which the server reports how
+// GetArrowData lays out record batches in its payloads: FramingStream, one
+// self-contained IPC stream per payload, or FramingMessages, the messages of
+// a single IPC stream spread over the payloads.
+const (
+	FramingHeader   = "arrowlink-framing"
+	FramingStream   = "stream"
+	FramingMessages = "messages"
+)
+
+// EndOfStream is the marker that ends an IPC stream.
+var EndOfStream = []byte{0xFF, 0xFF, 0xFF, 0xFF, 0, 0, 0, 0}
+
+// IPCMessage is a single encapsulated IPC message.
+type IPCMessage struct {
+	Type ipc.MessageType
+	Data []byte
+}
+
+// MessageEncoder serializes the record batches of a stream as individual IPC
+// messages rather than self-contained streams, so that the schema is encoded
+// once. The schema message, then every message returned by Encode in order,
+// then EndOfStream make up a valid IPC stream.
+type MessageEncoder struct {
+	schema []byte
+	pw     *messagePayloadWriter
+	writer *ipc.Writer
+}
+
+// NewMessageEncoder creates an encoder for batches of schema whose bodies are
+// compressed with codec. Close releases it.
+func NewMessageEncoder(schema *arrow.Schema, codec Codec, mem memory.Allocator) *MessageEncoder {
+	pw := &messagePayloadWriter{}
+	ps := ipc.GetSchemaPayload(schema, mem)
+	defer ps.Release()
+	pw.WritePayload(ps)
+	e := &MessageEncoder{schema: pw.messages[0].Data, pw: pw}
+	pw.messages = nil
+
+	opts := append([]ipc.Option{ipc.WithSchema(schema), ipc.WithAllocator(mem)}, codec.IPCOptions()...)
+	e.writer = ipc.NewWriterWithPayloadWriter(pw, opts...)
+	return e
+}
+
+// Schema returns the encapsulated schema message.
+func (e *MessageEncoder) Schema() []byte {
+	return e.schema
+}
+
+// Encode serializes record as the dictionary batches it needs, if any,
+// followed by its record batch.
+func (e *MessageEncoder) Encode(record arrow.Record) ([]IPCMessage, SerializeTimings, error) {
+	start := time.Now()
+	e.pw.writing = 0
+	err := e.writer.Write(record)
+	messages := e.pw.messages
+	e.pw.messages = nil
+	if err != nil {
+		return nil, SerializeTimings{}, err
+	}
+	total := time.Since(start)
+	return messages, SerializeTimings{Total: total, Encode: total - e.pw.writing}, nil
+}
+
+// Close releases the encoder's resources.
+func (e *MessageEncoder) Close() error {
+	return e.writer.Close()
+}
+
+// messagePayloadWriter collects each IPC payload as a separate message. The
+// schema is written by NewMessageEncoder, so the writer's own copy is
+// dropped.
+type messagePayloadWriter struct {
+	streamPayloadWriter
+	schemaWritten bool
+	messages      []IPCMessage
+}
+
+func (w *messagePayloadWriter) WritePayload(p ipc.Payload) error {
+	meta := p.Meta()
+	msg := ipc.NewMessage(meta, memory.NewBufferBytes(nil))
+	typ := msg.Type()
+	msg.Release()
+	meta.Release()
+	if typ == ipc.MessageSchema {
+		if w.schemaWritten {
+			return nil
+		}
+		w.schemaWritten = true
+	}
+
+	if err := w.streamPayloadWriter.WritePayload(p); err != nil {
+		return err
+	}
+	w.messages = append(w.messages, IPCMessage{Type: typ, Data: w.buf.Bytes()})
+	w.buf = bytes.Buffer{}
+	return nil
+}
+
+// Close discards the end-of-stream marker; the caller ends the stream.
+func (w *messagePayloadWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,108 @@
+package arrow
+
+import (
+	"context"
+	"sync"
+
+	arrow "github.com/apache/arrow-go/v18/arrow"
+)
+
+// batchWorker builds record batches by index. A worker is used by a single
+// goroutine, so it may keep builders and random sources across batches.
+type batchWorker interface {
+	build(batch int64) (arrow.Record, error)
+	release()
+}
+
+type builtBatch struct {
+	rec arrow.Record
+	err error
+}
+
+// parallelBatches builds batches first to n-1 on up to workers goroutines and
+// returns them in order. Nothing is built until next is first called, so a
+// reader that is only asked for its schema costs nothing; after that, at
+// most workers batches are built ahead of the consumer. next returns a nil
+// record once every batch has been returned; stop cancels the remaining
+// work, releases the batches built ahead and waits for the workers to
+// finish. stop must be called exactly once, and next not after it.
+func parallelBatches(ctx context.Context, first, n int64, workers int, newWorker func() batchWorker) (next func() (arrow.Record, error), stop func()) {
+	workers = max(1, min(workers, int(n-first)))
+	ctx, cancel := context.WithCancel(ctx)
+
+	type job struct {
+		batch  int64
+		result chan builtBatch
+	}
+	jobs := make(chan job)
+	// pending holds the result of every dispatched batch in stream order.
+	pending := make(chan chan builtBatch, workers)
+
+	var (
+		wg      sync.WaitGroup
+		once    sync.Once
+		started bool
+	)
+	start := func() {
+		started = true
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func(w batchWorker) {
+				defer wg.Done()
+				defer w.release()
+				for j := range jobs {
+					if err := ctx.Err(); err != nil {
+						j.result <- builtBatch{err: err}
+						continue
+					}
+					rec, err := w.build(j.batch)
+					j.result <- builtBatch{rec: rec, err: err}
+				}
+			}(newWorker())
+		}
+
+		go func() {
+			defer close(pending)
+			defer close(jobs)
+			for batch := first; batch < n; batch++ {
+				result := make(chan builtBatch, 1)
+				select {
+				case pending <- result:
+				case <-ctx.Done():
+					return
+				}
+				select {
+				case jobs <- job{batch: batch, result: result}:
+				case <-ctx.Done():
+					result <- builtBatch{err: ctx.Err()}
+					return
+				}
+			}
+		}()
+	}
+
+	next = func() (arrow.Record, error) {
+		once.Do(start)
+		result, ok := <-pending
+		if !ok {
+			return nil, ctx.Err()
+		}
+		b := <-result
+		return b.rec, b.err
+	}
+	stop = func() {
+		// A reader released before its first batch never starts
+		once.Do(func() {})
+		cancel()
+		if !started {
+			return
+		}
+		for result := range pending {
+			if b := <-result; b.rec != nil {
+				b.rec.Release()
+			}
+		}
+		wg.Wait()
+	}
+	return next, stop
+}
@@ -0,0 +1,86 @@
+package arrow
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// countingWorker builds single-row batches holding their index and counts
+// the workers created and the batches built.
+type countingWorker struct {
+	builder *array.RecordBuilder
+	built   *atomic.Int64
+}
+
+func (w *countingWorker) build(batch int64) (arrow.Record, error) {
+	w.built.Add(1)
+	w.builder.Field(0).(*array.Int64Builder).Append(batch)
+	return w.builder.NewRecord(), nil
+}
+
+func (w *countingWorker) release() {
+	w.builder.Release()
+}
+
+func countingBatches(t *testing.T, first, n int64, workers int) (next func() (arrow.Record, error), stop func(), created, built *atomic.Int64) {
+	t.Helper()
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	t.Cleanup(func() { mem.AssertSize(t, 0) })
+	schema := arrow.NewSchema([]arrow.Field{{Name: "batch", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	created, built = new(atomic.Int64), new(atomic.Int64)
+	next, stop = parallelBatches(context.Background(), first, n, workers, func() batchWorker {
+		created.Add(1)
+		return &countingWorker{builder: array.NewRecordBuilder(mem, schema), built: built}
+	})
+	return next, stop, created, built
+}
+
+func TestParallelBatchesInOrder(t *testing.T) {
+	next, stop, _, built := countingBatches(t, 3, 50, 4)
+	defer stop()
+	for want := int64(3); want < 50; want++ {
+		rec, err := next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := rec.Column(0).(*array.Int64).Value(0); got != want {
+			t.Fatalf("got batch %d, want %d", got, want)
+		}
+		rec.Release()
+	}
+	if rec, err := next(); rec != nil || err != nil {
+		t.Errorf("after the last batch: record %v, error %v", rec, err)
+	}
+	if built.Load() != 47 {
+		t.Errorf("built %d batches, want 47", built.Load())
+	}
+}
+
+func TestParallelBatchesAreLazy(t *testing.T) {
+	_, stop, created, built := countingBatches(t, 0, 100, 4)
+	time.Sleep(50 * time.Millisecond)
+	stop()
+	if created.Load() != 0 || built.Load() != 0 {
+		t.Errorf("created %d workers and built %d batches before the first read", created.Load(), built.Load())
+	}
+}
+
+func TestParallelBatchesStopEarly(t *testing.T) {
+	next, stop, _, built := countingBatches(t, 0, 100, 4)
+	rec, err := next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Release()
+	// The batches built ahead are released by stop
+	stop()
+	if n := built.Load(); n > 1+4+1 {
+		t.Errorf("built %d batches for a single read with 4 workers", n)
+	}
+}
@@ -0,0 +1,175 @@
+package arrow
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	arrow "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+)
+
+// ParquetOptions configures a Parquet-backed service.
+type ParquetOptions struct {
+	// Schema selects and orders the top-level columns to read by name; their
+	// types must match the files. When nil, every column of the first file is
+	// read.
+	Schema *arrow.Schema
+	// Parallel decodes the columns of each batch concurrently.
+	Parallel bool
+}
+
+type parquetService struct {
+	mem      memory.Allocator
+	files    []string
+	schema   *arrow.Schema
+	parallel bool
+	rows     int64
+}
+
+// NewParquetService serves a Parquet file, or every .parquet file under a
+// directory, as a single dataset. Only the file footers are read up front;
+// row groups are decoded lazily as the reader returned by GetData advances.
+func NewParquetService(path string, opts ParquetOptions) (ArrowService, error) {
+	files, err := listFiles(path, FormatParquet)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &parquetService{
+		mem:      memory.NewGoAllocator(),
+		files:    files,
+		schema:   opts.Schema,
+		parallel: opts.Parallel,
+	}
+	for _, path := range files {
+		if err := s.inspect(path); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return s, nil
+}
+
+// inspect reads the footer of path, adopting its schema if the service has
+// none yet and checking that it provides the service's columns.
+func (s *parquetService) inspect(path string) error {
+	pf, err := file.OpenParquetFile(path, false)
+	if err != nil {
+		return err
+	}
+	defer pf.Close()
+
+	fr, err := pqarrow.NewFileReader(pf, pqarrow.ArrowReadProperties{}, s.mem)
+	if err != nil {
+		return err
+	}
+	if s.schema == nil {
+		if s.schema, err = fr.Schema(); err != nil {
+			return err
+		}
+	} else if _, err := s.leafColumns(fr); err != nil {
+		return err
+	}
+	s.rows += pf.NumRows()
+	return nil
+}
+
+func (s *parquetService) EstimatedRows() int64 {
+	return s.rows
+}
+
+// CheckHealth reports whether every file is still readable.
+func (s *parquetService) CheckHealth(ctx context.Context) error {
+	return checkFiles(s.files)
+}
+
+// Snapshot changes whenever a file is modified.
+func (s *parquetService) Snapshot() string {
+	return filesSnapshot(s.files)
+}
+
+func (s *parquetService) GetData(ctx context.Context, opts ReadOptions) (array.RecordReader, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	props := pqarrow.ArrowReadProperties{
+		Parallel:  s.parallel,
+		BatchSize: int64(opts.batchSize()),
+	}
+	mem := opts.allocator(s.mem)
+	open := func(ctx context.Context, path string) (array.RecordReader, io.Closer, error) {
+		pf, err := file.OpenParquetFile(path, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		fr, err := pqarrow.NewFileReader(pf, props, mem)
+		if err != nil {
+			pf.Close()
+			return nil, nil, err
+		}
+		leaves, err := s.leafColumns(fr)
+		if err != nil {
+			pf.Close()
+			return nil, nil, err
+		}
+		rr, err := fr.GetRecordReader(ctx, leaves, nil)
+		if err != nil {
+			pf.Close()
+			return nil, nil, err
+		}
+		return rr, pf, nil
+	}
+	return readFiles(ctx, s.schema, s.files, opts.StartBatch, open, s.conform), nil
+}
+
+// leafColumns returns the indices of the leaf columns making up the service's
+// top-level fields in a file, failing if a field is missing or its type
+// differs.
+func (s *parquetService) leafColumns(fr *pqarrow.FileReader) ([]int, error) {
+	fileSchema, err := fr.Schema()
+	if err != nil {
+		return nil, err
+	}
+
+	// Group the file's leaf columns by the top-level field they belong to.
+	fieldLeaves := make(map[int][]int)
+	for leaf := 0; leaf < fr.ParquetReader().MetaData().Schema.NumColumns(); leaf++ {
+		fields, err := fr.Manifest.GetFieldIndices([]int{leaf})
+		if err != nil {
+			return nil, err
+		}
+		fieldLeaves[fields[0]] = append(fieldLeaves[fields[0]], leaf)
+	}
+
+	var leaves []int
+	for _, want := range s.schema.Fields() {
+		indices := fileSchema.FieldIndices(want.Name)
+		if len(indices) == 0 {
+			return nil, fmt.Errorf("column %q not found", want.Name)
+		}
+		got := fileSchema.Field(indices[0])
+		if !arrow.TypeEqual(want.Type, got.Type) {
+			return nil, fmt.Errorf("column %q has type %s, expected %s", want.Name, got.Type, want.Type)
+		}
+		leaves = append(leaves, fieldLeaves[indices[0]]...)
+	}
+	return leaves, nil
+}
+
+// conform reorders a batch's columns, which pqarrow returns in file order,
+// to match the service schema.
+func (s *parquetService) conform(rec arrow.Record) (arrow.Record, error) {
+	cols := make([]arrow.Array, s.schema.NumFields())
+	for i, f := range s.schema.Fields() {
+		indices := rec.Schema().FieldIndices(f.Name)
+		if len(indices) == 0 {
+			return nil, fmt.Errorf("column %q missing from batch", f.Name)
+		}
+		cols[i] = rec.Column(indices[0])
+	}
+	return array.NewRecord(s.schema, cols, rec.NumRows()), nil
+}
@@ -0,0 +1,129 @@
+package arrow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	arrow "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// ErrInvalidQuery is wrapped by errors caused by a malformed projection or
+// filter, as opposed to failures while reading data.
+var ErrInvalidQuery = errors.New("invalid query")
+
+// Query narrows a dataset to the columns and rows a client asked for.
+type Query struct {
+	// Columns lists the fields to return, in order. Empty returns every field.
+	Columns []string
+	// Filter is a filter expression as accepted by ParseFilter. Empty returns
+	// every row.
+	Filter string
+	// Limit caps the number of rows returned, counted after filtering. Zero
+	// or a negative value returns every row.
+	Limit int64
+}
+
+// IsEmpty reports whether the query selects the whole dataset.
+func (q Query) IsEmpty() bool {
+	return len(q.Columns) == 0 && q.Filter == "" && q.Limit <= 0
+}
+
+// ApplyQuery wraps reader so that every batch is filtered and then projected.
+// The query is validated against reader's schema before any batch is read.
+// On success the returned reader takes over the caller's reference to reader;
+// batches left empty by the filter are skipped. Once the limit is reached the
+// underlying reader is no longer advanced.
+func ApplyQuery(ctx context.Context, reader array.RecordReader, q Query) (array.RecordReader, error) {
+	if q.IsEmpty() {
+		return reader, nil
+	}
+
+	var filter *Filter
+	if q.Filter != "" {
+		var err error
+		filter, err = ParseFilter(q.Filter)
+		if err != nil {
+			return nil, err
+		}
+		if err := filter.Bind(reader.Schema()); err != nil {
+			return nil, err
+		}
+	}
+
+	schema, indices, err := projectSchema(reader.Schema(), q.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := q.Limit
+	next := func() (arrow.Record, error) {
+		for (q.Limit <= 0 || remaining > 0) && reader.Next() {
+			rec := reader.Record()
+			rec.Retain()
+			if filter != nil {
+				filtered, err := filter.Apply(ctx, rec)
+				rec.Release()
+				if err != nil {
+					return nil, err
+				}
+				rec = filtered
+			}
+			if rec.NumRows() == 0 {
+				rec.Release()
+				continue
+			}
+			if q.Limit > 0 {
+				if rec.NumRows() > remaining {
+					sliced := rec.NewSlice(0, remaining)
+					rec.Release()
+					rec = sliced
+				}
+				remaining -= rec.NumRows()
+			}
+			if indices != nil {
+				projected := projectRecord(schema, rec, indices)
+				rec.Release()
+				rec = projected
+			}
+			return rec, nil
+		}
+		return nil, reader.Err()
+	}
+	return newBatchReader(ctx, "ApplyQuery.FilterBatch", schema, next, reader.Release), nil
+}
+
+// projectSchema returns the schema holding only the named fields and their
+// indices in schema. No columns selects the whole schema and nil indices.
+func projectSchema(schema *arrow.Schema, columns []string) (*arrow.Schema, []int, error) {
+	if len(columns) == 0 {
+		return schema, nil, nil
+	}
+	seen := make(map[string]bool, len(columns))
+	fields := make([]arrow.Field, 0, len(columns))
+	indices := make([]int, 0, len(columns))
+	for _, name := range columns {
+		if seen[name] {
+			return nil, nil, fmt.Errorf("%w: column %q is listed twice", ErrInvalidQuery, name)
+		}
+		seen[name] = true
+
+		idx := schema.FieldIndices(name)
+		if len(idx) == 0 {
+			return nil, nil, fmt.Errorf("%w: unknown column %q", ErrInvalidQuery, name)
+		}
+		fields = append(fields, schema.Field(idx[0]))
+		indices = append(indices, idx[0])
+	}
+	md := schema.Metadata()
+	return arrow.NewSchema(fields, &md), indices, nil
+}
+
+func projectRecord(schema *arrow.Schema, record arrow.Record, indices []int) arrow.Record {
+	cols := make([]arrow.Array, len(indices))
+	for i, idx := range indices {
+		cols[i] = record.Column(idx)
+	}
+	return array.NewRecord(schema, cols, record.NumRows())
+}
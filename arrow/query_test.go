@@ -0,0 +1,198 @@
+package arrow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	arrow "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// nextCounter counts the calls to Next on a reader.
+type nextCounter struct {
+	array.RecordReader
+	calls int
+}
+
+func (r *nextCounter) Next() bool {
+	r.calls++
+	return r.RecordReader.Next()
+}
+
+// queryTestReader returns a reader over three copies of filterTestRecord,
+// ids 1 to 5 in each.
+func queryTestReader(t *testing.T, mem memory.Allocator) *nextCounter {
+	t.Helper()
+	record := filterTestRecord(mem)
+	defer record.Release()
+	reader, err := array.NewRecordReader(record.Schema(), []arrow.Record{record, record, record})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &nextCounter{RecordReader: reader}
+}
+
+func TestApplyQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query Query
+		// batches lists the ids of every batch returned.
+		batches [][]int64
+		columns []string
+		// calls is the number of calls to Next on the source, or zero not
+		// to check it.
+		calls int
+	}{
+		{
+			name:    "projection",
+			query:   Query{Columns: []string{"id", "category"}},
+			batches: [][]int64{{1, 2, 3, 4, 5}, {1, 2, 3, 4, 5}, {1, 2, 3, 4, 5}},
+			columns: []string{"id", "category"},
+		},
+		{
+			name:    "filter",
+			query:   Query{Filter: "category = 'B'"},
+			batches: [][]int64{{2, 5}, {2, 5}, {2, 5}},
+		},
+		{
+			name:    "batches emptied by the filter are skipped",
+			query:   Query{Filter: "id > 10"},
+			batches: nil,
+			calls:   4,
+		},
+		{
+			name:    "filter on a column that is not returned",
+			query:   Query{Columns: []string{"id"}, Filter: "flag"},
+			batches: [][]int64{{1, 3, 5}, {1, 3, 5}, {1, 3, 5}},
+			columns: []string{"id"},
+		},
+		{
+			name:    "limit within a batch",
+			query:   Query{Limit: 3},
+			batches: [][]int64{{1, 2, 3}},
+			calls:   1,
+		},
+		{
+			name:    "limit across batches",
+			query:   Query{Limit: 7},
+			batches: [][]int64{{1, 2, 3, 4, 5}, {1, 2}},
+			calls:   2,
+		},
+		{
+			name:    "limit on a batch boundary",
+			query:   Query{Limit: 10},
+			batches: [][]int64{{1, 2, 3, 4, 5}, {1, 2, 3, 4, 5}},
+			calls:   2,
+		},
+		{
+			name:    "limit counts filtered rows",
+			query:   Query{Filter: "category = 'A'", Limit: 3},
+			batches: [][]int64{{1, 4}, {1}},
+			calls:   2,
+		},
+		{
+			name:    "limit beyond the data",
+			query:   Query{Limit: 100},
+			batches: [][]int64{{1, 2, 3, 4, 5}, {1, 2, 3, 4, 5}, {1, 2, 3, 4, 5}},
+			calls:   4,
+		},
+		{
+			name:    "negative limit returns every row",
+			query:   Query{Limit: -1},
+			batches: [][]int64{{1, 2, 3, 4, 5}, {1, 2, 3, 4, 5}, {1, 2, 3, 4, 5}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+			source := queryTestReader(t, mem)
+
+			reader, err := ApplyQuery(context.Background(), source, tt.query)
+			if err != nil {
+				source.Release()
+				t.Fatalf("ApplyQuery: %v", err)
+			}
+			defer reader.Release()
+
+			if tt.columns != nil {
+				var names []string
+				for _, f := range reader.Schema().Fields() {
+					names = append(names, f.Name)
+				}
+				if !reflect.DeepEqual(names, tt.columns) {
+					t.Errorf("columns = %v, want %v", names, tt.columns)
+				}
+			}
+			var batches [][]int64
+			for reader.Next() {
+				if !reader.Schema().Equal(reader.Record().Schema()) {
+					t.Errorf("batch schema %s differs from reader schema %s", reader.Record().Schema(), reader.Schema())
+				}
+				batches = append(batches, recordIDs(reader.Record()))
+			}
+			if err := reader.Err(); err != nil {
+				t.Fatalf("Err: %v", err)
+			}
+			if !reflect.DeepEqual(batches, tt.batches) {
+				t.Errorf("batches = %v, want %v", batches, tt.batches)
+			}
+			if reader.Next() {
+				t.Error("Next returned true after the end")
+			}
+			if tt.calls > 0 && source.calls != tt.calls {
+				t.Errorf("source Next called %d times, want %d", source.calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestApplyQueryEmpty(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+	source := queryTestReader(t, mem)
+
+	reader, err := ApplyQuery(context.Background(), source, Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Release()
+	if reader != array.RecordReader(source) {
+		t.Error("an empty query should return the reader unchanged")
+	}
+}
+
+func TestApplyQueryErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query Query
+	}{
+		{"unknown column", Query{Columns: []string{"id", "missing"}}},
+		{"duplicate column", Query{Columns: []string{"id", "id"}}},
+		{"malformed filter", Query{Filter: "id ="}},
+		{"unknown filter field", Query{Filter: "missing = 1"}},
+		{"mistyped literal", Query{Filter: "value = 'abc'"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+			source := queryTestReader(t, mem)
+			defer source.Release()
+
+			_, err := ApplyQuery(context.Background(), source, tt.query)
+			if err == nil {
+				t.Fatal("ApplyQuery succeeded, want an error")
+			}
+			if !errors.Is(err, ErrInvalidQuery) {
+				t.Errorf("error %v does not wrap ErrInvalidQuery", err)
+			}
+			if source.calls != 0 {
+				t.Errorf("source read %d times before the query was validated", source.calls)
+			}
+		})
+	}
+}
@@ -3,6 +3,11 @@ package arrow
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/apache/arrow-go/v18/arrow"
@@ -25,33 +30,77 @@ func NewArrowReader(data []byte) *ArrowReader {
 	}
 }
 
-// ToJSON converts Arrow data to JSON
-func (r *ArrowReader) ToJSON() ([]byte, error) {
+// ForEach calls fn for every record batch in the IPC stream, in order. The
+// record is only valid during the call; fn must Retain it to keep it.
+func (r *ArrowReader) ForEach(fn func(record arrow.Record) error) error {
 	reader, err := ipc.NewReader(bytes.NewReader(r.data), ipc.WithAllocator(r.mem))
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer reader.Release()
 
-	record, err := reader.Read()
-	if err != nil {
-		return nil, err
+	for reader.Next() {
+		if err := fn(reader.Record()); err != nil {
+			return err
+		}
 	}
-	defer record.Release()
-
-	// Convert to Go-friendly structure
-	rows := make([]map[string]interface{}, record.NumRows())
-	schema := record.Schema()
+	if err := reader.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
 
-	for i := 0; i < int(record.NumRows()); i++ {
-		row := make(map[string]interface{})
-		for j, col := range record.Columns() {
-			field := schema.Field(j)
-			row[field.Name] = getValueAt(col, i)
+// ToRows converts every row of every batch to a map from column name to a Go
+// value. See ToJSON for the value of each Arrow type.
+func (r *ArrowReader) ToRows() ([]map[string]interface{}, error) {
+	rows := make([]map[string]interface{}, 0)
+	err := r.ForEach(func(record arrow.Record) error {
+		schema := record.Schema()
+		for i := 0; i < int(record.NumRows()); i++ {
+			row := make(map[string]interface{}, record.NumCols())
+			for j, col := range record.Columns() {
+				row[schema.Field(j).Name] = getValueAt(col, i)
+			}
+			rows = append(rows, row)
 		}
-		rows[i] = row
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return rows, nil
+}
 
+// ToJSON converts Arrow data to a JSON array with one object per row. Values
+// are encoded as follows:
+//
+//   - null values of any type: null
+//   - booleans, integers of every width and signedness: JSON booleans and
+//     numbers; 64-bit integers are written exactly
+//   - float16/32/64: numbers, except NaN and ±Inf, which are the strings
+//     "NaN", "Infinity" and "-Infinity"
+//   - decimal128/256: strings holding the exact decimal, e.g. "123.45"
+//   - strings: strings; binary, fixed-size binary and binary views: base64
+//   - date32/64: "2006-01-02"
+//   - time32/64: "15:04:05" with as many fractional digits as the unit
+//   - timestamps: RFC 3339 with nanoseconds, in the type's time zone or UTC
+//   - durations: Go duration strings such as "1h2m3.5s"; those beyond about
+//     292 years, which Go cannot represent, are the number and unit, e.g.
+//     "9223372036854775807s"
+//   - intervals: objects with "months", "days", "milliseconds" and
+//     "nanoseconds" members, as applicable
+//   - lists of every kind: arrays
+//   - structs: objects keyed by field name
+//   - maps: arrays of {"key": ..., "value": ...} objects, preserving order and
+//     allowing non-string keys
+//   - dictionaries and run-end encoded arrays: the decoded value
+//   - unions: the value of the selected child
+//   - extension types: the value of their storage
+func (r *ArrowReader) ToJSON() ([]byte, error) {
+	rows, err := r.ToRows()
+	if err != nil {
+		return nil, err
+	}
 	return json.MarshalIndent(rows, "", "  ")
 }
 
@@ -62,18 +111,138 @@ func getValueAt(arr arrow.Array, i int) interface{} {
 	}
 
 	switch arr := arr.(type) {
+	case *array.Null:
+		return nil
+	case *array.Boolean:
+		return arr.Value(i)
+	case *array.Int8:
+		return arr.Value(i)
+	case *array.Int16:
+		return arr.Value(i)
+	case *array.Int32:
+		return arr.Value(i)
 	case *array.Int64:
 		return arr.Value(i)
-	case *array.Float64:
+	case *array.Uint8:
 		return arr.Value(i)
-	case *array.String:
+	case *array.Uint16:
 		return arr.Value(i)
-	case *array.Boolean:
+	case *array.Uint32:
 		return arr.Value(i)
+	case *array.Uint64:
+		return arr.Value(i)
+	case *array.Float16:
+		return jsonFloat(arr.Value(i).Float32())
+	case *array.Float32:
+		return jsonFloat(arr.Value(i))
+	case *array.Float64:
+		return jsonFloat(arr.Value(i))
+	case *array.Decimal128:
+		return arr.Value(i).ToString(arr.DataType().(*arrow.Decimal128Type).Scale)
+	case *array.Decimal256:
+		return arr.Value(i).ToString(arr.DataType().(*arrow.Decimal256Type).Scale)
+	case *array.String:
+		return strings.Clone(arr.Value(i))
+	case *array.LargeString:
+		return strings.Clone(arr.Value(i))
+	case *array.StringView:
+		return strings.Clone(arr.Value(i))
+	case *array.Binary:
+		return append([]byte{}, arr.Value(i)...)
+	case *array.LargeBinary:
+		return append([]byte{}, arr.Value(i)...)
+	case *array.BinaryView:
+		return append([]byte{}, arr.Value(i)...)
+	case *array.FixedSizeBinary:
+		return append([]byte{}, arr.Value(i)...)
+	case *array.Date32:
+		return arr.Value(i).FormattedString()
+	case *array.Date64:
+		return arr.Value(i).FormattedString()
+	case *array.Time32:
+		return arr.Value(i).FormattedString(arr.DataType().(*arrow.Time32Type).Unit)
+	case *array.Time64:
+		return arr.Value(i).FormattedString(arr.DataType().(*arrow.Time64Type).Unit)
 	case *array.Timestamp:
 		tsType := arr.DataType().(*arrow.TimestampType)
-		return arr.Value(i).ToTime(tsType.Unit).Format(time.RFC3339)
+		t := arr.Value(i).ToTime(tsType.Unit)
+		if loc, err := tsType.GetZone(); err == nil && loc != nil {
+			t = t.In(loc)
+		}
+		return t.Format(time.RFC3339Nano)
+	case *array.Duration:
+		return durationString(int64(arr.Value(i)), arr.DataType().(*arrow.DurationType).Unit)
+	case *array.MonthInterval:
+		return map[string]interface{}{"months": arr.Value(i)}
+	case *array.DayTimeInterval:
+		v := arr.Value(i)
+		return map[string]interface{}{"days": v.Days, "milliseconds": v.Milliseconds}
+	case *array.MonthDayNanoInterval:
+		v := arr.Value(i)
+		return map[string]interface{}{"months": v.Months, "days": v.Days, "nanoseconds": v.Nanoseconds}
+	case *array.Map:
+		// Map must be matched before the list types it is built on.
+		start, end := arr.ValueOffsets(i)
+		keys, items := arr.Keys(), arr.Items()
+		entries := make([]interface{}, 0, end-start)
+		for j := int(start); j < int(end); j++ {
+			entries = append(entries, map[string]interface{}{
+				"key":   getValueAt(keys, j),
+				"value": getValueAt(items, j),
+			})
+		}
+		return entries
+	case array.ListLike:
+		start, end := arr.ValueOffsets(i)
+		values := arr.ListValues()
+		list := make([]interface{}, 0, end-start)
+		for j := int(start); j < int(end); j++ {
+			list = append(list, getValueAt(values, j))
+		}
+		return list
+	case *array.Struct:
+		st := arr.DataType().(*arrow.StructType)
+		obj := make(map[string]interface{}, arr.NumField())
+		for k := 0; k < arr.NumField(); k++ {
+			obj[st.Field(k).Name] = getValueAt(arr.Field(k), i)
+		}
+		return obj
+	case *array.Dictionary:
+		return getValueAt(arr.Dictionary(), arr.GetValueIndex(i))
+	case *array.RunEndEncoded:
+		return getValueAt(arr.Values(), arr.GetPhysicalIndex(i))
+	case *array.SparseUnion:
+		return getValueAt(arr.Field(arr.ChildID(i)), i)
+	case *array.DenseUnion:
+		return getValueAt(arr.Field(arr.ChildID(i)), int(arr.ValueOffset(i)))
+	case array.ExtensionArray:
+		return getValueAt(arr.Storage(), i)
+	default:
+		return arr.ValueStr(i)
+	}
+}
+
+// durationString formats a duration as a Go duration string, or as the
+// number and unit, e.g. "9223372036854775807s", when it does not fit in a
+// time.Duration.
+func durationString(v int64, unit arrow.TimeUnit) string {
+	m := int64(unit.Multiplier())
+	if v > math.MaxInt64/m || v < math.MinInt64/m {
+		return strconv.FormatInt(v, 10) + unit.String()
+	}
+	return time.Duration(v * m).String()
+}
+
+// jsonFloat returns f, or a string for the values JSON cannot represent.
+func jsonFloat[F float32 | float64](f F) interface{} {
+	switch v := float64(f); {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "Infinity"
+	case math.IsInf(v, -1):
+		return "-Infinity"
 	default:
-		return "unsupported type"
+		return f
 	}
 }
@@ -0,0 +1,211 @@
+package arrow
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	arrow "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/extensions"
+	"github.com/apache/arrow-go/v18/arrow/float16"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// fromJSON builds an array of type dt from JSON values.
+func fromJSON(dt arrow.DataType, values string) func(memory.Allocator) (arrow.Array, error) {
+	return func(mem memory.Allocator) (arrow.Array, error) {
+		arr, _, err := array.FromJSON(mem, dt, strings.NewReader(values))
+		return arr, err
+	}
+}
+
+func TestGetValueAt(t *testing.T) {
+	utc := &arrow.TimestampType{Unit: arrow.Millisecond, TimeZone: "UTC"}
+	paris := &arrow.TimestampType{Unit: arrow.Second, TimeZone: "Europe/Paris"}
+	dictType := &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int8, ValueType: arrow.BinaryTypes.String}
+	unionTypes := []arrow.Field{
+		{Name: "i", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "s", Type: arrow.BinaryTypes.String, Nullable: true},
+	}
+
+	for _, tc := range []struct {
+		name  string
+		build func(memory.Allocator) (arrow.Array, error)
+		// want holds the value of every row
+		want []interface{}
+	}{
+		{"null", fromJSON(arrow.Null, `[null]`), []interface{}{nil}},
+		{"bool", fromJSON(arrow.FixedWidthTypes.Boolean, `[true, null, false]`), []interface{}{true, nil, false}},
+		{"int8", fromJSON(arrow.PrimitiveTypes.Int8, `[-128, 127]`), []interface{}{int8(-128), int8(127)}},
+		{"int16", fromJSON(arrow.PrimitiveTypes.Int16, `[-32768]`), []interface{}{int16(-32768)}},
+		{"int32", fromJSON(arrow.PrimitiveTypes.Int32, `[2147483647]`), []interface{}{int32(2147483647)}},
+		{"int64", func(mem memory.Allocator) (arrow.Array, error) {
+			// FromJSON decodes numbers as float64, which would round these
+			b := array.NewInt64Builder(mem)
+			defer b.Release()
+			b.Append(9007199254740993)
+			return b.NewArray(), nil
+		}, []interface{}{int64(9007199254740993)}},
+		{"uint8", fromJSON(arrow.PrimitiveTypes.Uint8, `[255]`), []interface{}{uint8(255)}},
+		{"uint16", fromJSON(arrow.PrimitiveTypes.Uint16, `[65535]`), []interface{}{uint16(65535)}},
+		{"uint32", fromJSON(arrow.PrimitiveTypes.Uint32, `[4294967295]`), []interface{}{uint32(4294967295)}},
+		{"uint64", func(mem memory.Allocator) (arrow.Array, error) {
+			b := array.NewUint64Builder(mem)
+			defer b.Release()
+			b.Append(math.MaxUint64)
+			return b.NewArray(), nil
+		}, []interface{}{uint64(18446744073709551615)}},
+		{"float16", func(mem memory.Allocator) (arrow.Array, error) {
+			b := array.NewFloat16Builder(mem)
+			defer b.Release()
+			b.Append(float16.New(1.5))
+			return b.NewArray(), nil
+		}, []interface{}{float32(1.5)}},
+		{"float32", fromJSON(arrow.PrimitiveTypes.Float32, `[0.25]`), []interface{}{float32(0.25)}},
+		{"float64", func(mem memory.Allocator) (arrow.Array, error) {
+			b := array.NewFloat64Builder(mem)
+			defer b.Release()
+			b.AppendValues([]float64{2.5, math.Inf(1), math.Inf(-1), math.NaN()}, nil)
+			return b.NewArray(), nil
+		}, []interface{}{2.5, "Infinity", "-Infinity", "NaN"}},
+		{"decimal128", fromJSON(&arrow.Decimal128Type{Precision: 10, Scale: 2}, `["123.45", "-0.01"]`), []interface{}{"123.45", "-0.01"}},
+		{"decimal256", fromJSON(&arrow.Decimal256Type{Precision: 40, Scale: 3}, `["12345678901234567890.123"]`), []interface{}{"12345678901234567890.123"}},
+		{"string", fromJSON(arrow.BinaryTypes.String, `["a", ""]`), []interface{}{"a", ""}},
+		{"large string", fromJSON(arrow.BinaryTypes.LargeString, `["b"]`), []interface{}{"b"}},
+		{"string view", fromJSON(arrow.BinaryTypes.StringView, `["a string longer than twelve bytes"]`), []interface{}{"a string longer than twelve bytes"}},
+		{"binary", fromJSON(arrow.BinaryTypes.Binary, `["AQI="]`), []interface{}{[]byte{1, 2}}},
+		{"large binary", fromJSON(arrow.BinaryTypes.LargeBinary, `["Aw=="]`), []interface{}{[]byte{3}}},
+		{"binary view", fromJSON(arrow.BinaryTypes.BinaryView, `["BA=="]`), []interface{}{[]byte{4}}},
+		{"fixed size binary", fromJSON(&arrow.FixedSizeBinaryType{ByteWidth: 2}, `["BQY="]`), []interface{}{[]byte{5, 6}}},
+		{"date32", fromJSON(arrow.FixedWidthTypes.Date32, `["2024-02-29"]`), []interface{}{"2024-02-29"}},
+		{"date64", fromJSON(arrow.FixedWidthTypes.Date64, `["1970-01-02"]`), []interface{}{"1970-01-02"}},
+		{"time32", fromJSON(arrow.FixedWidthTypes.Time32ms, `["13:14:15.250"]`), []interface{}{"13:14:15.250"}},
+		{"time64", fromJSON(arrow.FixedWidthTypes.Time64ns, `["01:02:03.000000004"]`), []interface{}{"01:02:03.000000004"}},
+		{"timestamp", fromJSON(utc, `["2024-01-02T03:04:05.678Z"]`), []interface{}{"2024-01-02T03:04:05.678Z"}},
+		{"timestamp with zone", fromJSON(paris, `[0]`), []interface{}{"1970-01-01T01:00:00+01:00"}},
+		{"duration", fromJSON(arrow.FixedWidthTypes.Duration_ms, `[3723500, -1]`), []interface{}{"1h2m3.5s", "-1ms"}},
+		{"duration beyond time.Duration", func(mem memory.Allocator) (arrow.Array, error) {
+			b := array.NewDurationBuilder(mem, arrow.FixedWidthTypes.Duration_s.(*arrow.DurationType))
+			defer b.Release()
+			b.AppendValues([]arrow.Duration{math.MaxInt64, math.MinInt64}, nil)
+			return b.NewArray(), nil
+		},
+			[]interface{}{"9223372036854775807s", "-9223372036854775808s"}},
+		{"month interval", func(mem memory.Allocator) (arrow.Array, error) {
+			b := array.NewMonthIntervalBuilder(mem)
+			defer b.Release()
+			b.Append(14)
+			return b.NewArray(), nil
+		}, []interface{}{map[string]interface{}{"months": arrow.MonthInterval(14)}}},
+		{"day time interval", fromJSON(arrow.FixedWidthTypes.DayTimeInterval, `[{"days": 2, "milliseconds": 500}]`),
+			[]interface{}{map[string]interface{}{"days": int32(2), "milliseconds": int32(500)}}},
+		{"month day nano interval", fromJSON(arrow.FixedWidthTypes.MonthDayNanoInterval, `[{"months": 1, "days": 2, "nanoseconds": 3}]`),
+			[]interface{}{map[string]interface{}{"months": int32(1), "days": int32(2), "nanoseconds": int64(3)}}},
+		{"list", fromJSON(arrow.ListOf(arrow.PrimitiveTypes.Int32), `[[1, null], [], null]`),
+			[]interface{}{[]interface{}{int32(1), nil}, []interface{}{}, nil}},
+		{"large list", fromJSON(arrow.LargeListOf(arrow.BinaryTypes.String), `[["x"]]`), []interface{}{[]interface{}{"x"}}},
+		{"fixed size list", fromJSON(arrow.FixedSizeListOf(2, arrow.PrimitiveTypes.Int8), `[[1, 2]]`), []interface{}{[]interface{}{int8(1), int8(2)}}},
+		{"list view", fromJSON(arrow.ListViewOf(arrow.PrimitiveTypes.Int64), `[[7, 8]]`), []interface{}{[]interface{}{int64(7), int64(8)}}},
+		{"struct", fromJSON(arrow.StructOf(
+			arrow.Field{Name: "a", Type: arrow.PrimitiveTypes.Int64},
+			arrow.Field{Name: "b", Type: arrow.BinaryTypes.String, Nullable: true},
+		), `[{"a": 1, "b": null}]`), []interface{}{map[string]interface{}{"a": int64(1), "b": nil}}},
+		{"map", fromJSON(arrow.MapOf(arrow.PrimitiveTypes.Int32, arrow.BinaryTypes.String), `[[{"key": 2, "value": "two"}, {"key": 1, "value": null}]]`),
+			[]interface{}{[]interface{}{
+				map[string]interface{}{"key": int32(2), "value": "two"},
+				map[string]interface{}{"key": int32(1), "value": nil},
+			}}},
+		{"dictionary", func(mem memory.Allocator) (arrow.Array, error) {
+			b := array.NewDictionaryBuilder(mem, dictType).(*array.BinaryDictionaryBuilder)
+			defer b.Release()
+			for _, s := range []string{"red", "blue", "red"} {
+				if err := b.AppendString(s); err != nil {
+					return nil, err
+				}
+			}
+			return b.NewArray(), nil
+		}, []interface{}{"red", "blue", "red"}},
+		{"run end encoded", func(mem memory.Allocator) (arrow.Array, error) {
+			b := array.NewRunEndEncodedBuilder(mem, arrow.PrimitiveTypes.Int32, arrow.BinaryTypes.String)
+			defer b.Release()
+			values := b.ValueBuilder().(*array.StringBuilder)
+			b.Append(2)
+			values.Append("a")
+			b.Append(1)
+			values.Append("b")
+			return b.NewArray(), nil
+		}, []interface{}{"a", "a", "b"}},
+		{"sparse union", fromJSON(arrow.SparseUnionOf(unionTypes, []arrow.UnionTypeCode{0, 1}), `[[0, 5], [1, "five"]]`),
+			[]interface{}{int32(5), "five"}},
+		{"dense union", fromJSON(arrow.DenseUnionOf(unionTypes, []arrow.UnionTypeCode{0, 1}), `[[1, "six"], [0, 6]]`),
+			[]interface{}{"six", int32(6)}},
+		{"extension", fromJSON(extensions.NewUUIDType(), `["00000000-0000-0000-0000-000000000001"]`),
+			[]interface{}{append(make([]byte, 15), 1)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+			arr, err := tc.build(mem)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer arr.Release()
+			if arr.Len() != len(tc.want) {
+				t.Fatalf("array has %d rows, want %d", arr.Len(), len(tc.want))
+			}
+			for i, want := range tc.want {
+				if got := getValueAt(arr, i); !reflect.DeepEqual(got, want) {
+					t.Errorf("row %d = %#v, want %#v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestToJSON(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "wait", Type: arrow.FixedWidthTypes.Duration_s, Nullable: true},
+		{Name: "score", Type: arrow.PrimitiveTypes.Float64},
+	}, nil)
+	b := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer b.Release()
+	b.Field(0).(*array.Int64Builder).AppendValues([]int64{9007199254740993, 2}, nil)
+	b.Field(1).(*array.DurationBuilder).AppendValues([]arrow.Duration{90, math.MaxInt64}, nil)
+	b.Field(2).(*array.Float64Builder).AppendValues([]float64{1.5, 0}, nil)
+	rec := b.NewRecord()
+	defer rec.Release()
+	var buf bytes.Buffer
+	w := ipc.NewWriter(&buf, ipc.WithSchema(schema))
+	if err := w.Write(rec); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := NewArrowReader(buf.Bytes()).ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	json.Unmarshal([]byte(`[
+		{"id": 9007199254740993, "wait": "1m30s", "score": 1.5},
+		{"id": 2, "wait": "9223372036854775807s", "score": 0}
+	]`), &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToJSON() = %s", data)
+	}
+	// Unmarshalling into float64 would hide a rounded id
+	if !bytes.Contains(data, []byte("9007199254740993")) {
+		t.Errorf("ToJSON() does not write the int64 id exactly:\n%s", data)
+	}
+}
@@ -1,16 +1,59 @@
 package arrow
 
 import (
-	"bytes"
+	"context"
+	"runtime"
 
 	arrow "github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
-	"github.com/apache/arrow-go/v18/arrow/ipc"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 )
 
+// ReadOptions controls how an ArrowService produces its record batches.
+type ReadOptions struct {
+	// BatchSize is the maximum number of rows per record batch. Zero or a
+	// negative value selects DefaultBatchSize.
+	BatchSize int
+	// Allocator allocates the batches. Nil selects the service's own
+	// allocator.
+	Allocator memory.Allocator
+	// Parallelism is the number of goroutines a service may use to build
+	// batches concurrently. Zero or a negative value selects GOMAXPROCS.
+	// Services that read files ignore it.
+	Parallelism int
+	// StartBatch skips the first batches of the dataset, to resume a stream
+	// after the last batch a client received. Services that implement
+	// Snapshotter honor it; others may ignore it.
+	StartBatch int64
+}
+
+func (o ReadOptions) batchSize() int {
+	if o.BatchSize <= 0 {
+		return DefaultBatchSize
+	}
+	return o.BatchSize
+}
+
+func (o ReadOptions) parallelism() int {
+	if o.Parallelism <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return o.Parallelism
+}
+
+func (o ReadOptions) allocator(fallback memory.Allocator) memory.Allocator {
+	if o.Allocator == nil {
+		return fallback
+	}
+	return o.Allocator
+}
+
+// ArrowService produces a dataset as a sequence of record batches.
 type ArrowService interface {
-	GetData() ([]byte, error)
+	// GetData returns a reader over the dataset. Batches are produced lazily
+	// as the reader is advanced, and the reader stops with ctx.Err() once ctx
+	// is done. The caller must Release the reader.
+	GetData(ctx context.Context, opts ReadOptions) (array.RecordReader, error)
 }
 
 type arrowService struct {
@@ -23,7 +66,20 @@ func NewArrowService() ArrowService {
 	}
 }
 
-func (s *arrowService) GetData() ([]byte, error) {
+func (s *arrowService) EstimatedRows() int64 {
+	return 1
+}
+
+// Snapshot reports a constant, since the data never changes.
+func (s *arrowService) Snapshot() string {
+	return "static"
+}
+
+func (s *arrowService) GetData(ctx context.Context, opts ReadOptions) (array.RecordReader, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Define Arrow schema
 	schema := arrow.NewSchema([]arrow.Field{
 		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
@@ -31,7 +87,7 @@ func (s *arrowService) GetData() ([]byte, error) {
 	}, nil)
 
 	// Create record builder
-	builder := array.NewRecordBuilder(s.mem, schema)
+	builder := array.NewRecordBuilder(opts.allocator(s.mem), schema)
 	defer builder.Release()
 
 	// Populate data
@@ -44,13 +100,20 @@ func (s *arrowService) GetData() ([]byte, error) {
 	record := builder.NewRecord()
 	defer record.Release()
 
-	// Serialize record using Arrow IPC
-	var buf bytes.Buffer
-	writer := ipc.NewWriter(&buf, ipc.WithSchema(schema))
-	if err := writer.Write(record); err != nil {
-		return nil, err
+	records := []arrow.Record{record}
+	if opts.StartBatch > 0 {
+		records = nil
 	}
-	writer.Close()
+	return array.NewRecordReader(schema, records)
+}
 
-	return buf.Bytes(), nil
+// ServiceSchema returns the schema of the records produced by service. Readers
+// are lazy, so no batch is built.
+func ServiceSchema(ctx context.Context, service ArrowService) (*arrow.Schema, error) {
+	reader, err := service.GetData(ctx, ReadOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Release()
+	return reader.Schema(), nil
 }
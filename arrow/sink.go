@@ -0,0 +1,129 @@
+package arrow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	arrow "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// ArrowSink receives the record batches that clients upload through SendArrowData.
+type ArrowSink interface {
+	// Schema returns the schema uploads must match, or nil to accept any schema.
+	Schema() *arrow.Schema
+	// Write consumes a single record batch. Implementations that keep the
+	// record after Write returns must Retain it.
+	Write(record arrow.Record) error
+}
+
+// MemorySink keeps every uploaded record batch in memory.
+type MemorySink struct {
+	mu      sync.Mutex
+	schema  *arrow.Schema
+	records []arrow.Record
+}
+
+// NewMemorySink creates a MemorySink that only accepts batches matching schema.
+// A nil schema accepts any batch.
+func NewMemorySink(schema *arrow.Schema) *MemorySink {
+	return &MemorySink{schema: schema}
+}
+
+func (s *MemorySink) Schema() *arrow.Schema {
+	return s.schema
+}
+
+func (s *MemorySink) Write(record arrow.Record) error {
+	record.Retain()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+// Records returns the batches received so far. The caller must not release them.
+func (s *MemorySink) Records() []arrow.Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]arrow.Record(nil), s.records...)
+}
+
+// GetData serves the batches received so far, as they were uploaded, so
+// that the sink can be published as a dataset. It requires a schema, which
+// every batch is given since their fields may differ in nullability.
+func (s *MemorySink) GetData(ctx context.Context, opts ReadOptions) (array.RecordReader, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if s.schema == nil {
+		return nil, errors.New("a memory sink without a schema cannot be read")
+	}
+	var records []arrow.Record
+	for _, rec := range s.Records() {
+		records = append(records, array.NewRecord(s.schema, rec.Columns(), rec.NumRows()))
+	}
+	defer func() {
+		for _, rec := range records {
+			rec.Release()
+		}
+	}()
+	return array.NewRecordReader(s.schema, records)
+}
+
+// Release frees every batch held by the sink.
+func (s *MemorySink) Release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, rec := range s.records {
+		rec.Release()
+	}
+	s.records = nil
+}
+
+type discardSink struct {
+	schema *arrow.Schema
+}
+
+// NewDiscardSink creates a sink that validates uploads against schema and then
+// drops them. A nil schema accepts any batch.
+func NewDiscardSink(schema *arrow.Schema) ArrowSink {
+	return &discardSink{schema: schema}
+}
+
+func (s *discardSink) Schema() *arrow.Schema {
+	return s.schema
+}
+
+func (s *discardSink) Write(record arrow.Record) error {
+	return nil
+}
+
+// ErrSchemaMismatch is wrapped by the errors of CheckSchema.
+var ErrSchemaMismatch = errors.New("schema mismatch")
+
+// CheckSchema reports whether batches with schema actual can be written to a
+// sink expecting schema expected. Field names and types must match in order;
+// nullability and metadata are ignored since most producers mark every field
+// nullable.
+func CheckSchema(expected, actual *arrow.Schema) error {
+	if expected == nil {
+		return nil
+	}
+	if expected.NumFields() != actual.NumFields() {
+		return fmt.Errorf("%w: expected %d fields, got %d", ErrSchemaMismatch, expected.NumFields(), actual.NumFields())
+	}
+	for i, want := range expected.Fields() {
+		got := actual.Field(i)
+		if want.Name != got.Name {
+			return fmt.Errorf("%w: field %d is %q, expected %q", ErrSchemaMismatch, i, got.Name, want.Name)
+		}
+		if !arrow.TypeEqual(want.Type, got.Type) {
+			return fmt.Errorf("%w: field %q has type %s, expected %s", ErrSchemaMismatch, want.Name, got.Type, want.Type)
+		}
+	}
+	return nil
+}
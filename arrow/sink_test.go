@@ -0,0 +1,52 @@
+package arrow
+
+import (
+	"context"
+	"testing"
+
+	arrow "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func TestMemorySinkGetData(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	// Uploads may mark the field nullable where the sink does not
+	schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	uploaded := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true}}, nil)
+	sink := NewMemorySink(schema)
+	builder := array.NewRecordBuilder(mem, uploaded)
+	for _, rows := range []int{3, 2} {
+		for i := 0; i < rows; i++ {
+			builder.Field(0).(*array.Int64Builder).Append(int64(i))
+		}
+		rec := builder.NewRecord()
+		if err := CheckSchema(schema, rec.Schema()); err != nil {
+			t.Fatal(err)
+		}
+		if err := sink.Write(rec); err != nil {
+			t.Fatal(err)
+		}
+		rec.Release()
+	}
+	builder.Release()
+
+	reader, err := sink.GetData(context.Background(), ReadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows int64
+	for reader.Next() {
+		if !reader.Record().Schema().Equal(schema) {
+			t.Errorf("batch schema = %s, want the sink's", reader.Record().Schema())
+		}
+		rows += reader.Record().NumRows()
+	}
+	reader.Release()
+	if rows != 5 {
+		t.Errorf("read %d rows, want 5", rows)
+	}
+	sink.Release()
+}
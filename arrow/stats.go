@@ -0,0 +1,79 @@
+package arrow
+
+import (
+	"strconv"
+	"time"
+)
+
+// Trailer metadata keys carrying StreamStats. Durations are in milliseconds.
+const (
+	GenerationTrailer    = "arrowlink-generation-ms"
+	SerializationTrailer = "arrowlink-serialization-ms"
+	CompressionTrailer   = "arrowlink-compression-ms"
+	RowsTrailer          = "arrowlink-rows"
+	BatchesTrailer       = "arrowlink-batches"
+	BytesTrailer         = "arrowlink-bytes"
+)
+
+// StreamStats describes the work done by the server for one streaming call.
+type StreamStats struct {
+	// Generation is the time spent waiting for the dataset to produce
+	// batches, filtering and projection included.
+	Generation time.Duration
+	// Serialization is the time spent serializing batches to IPC,
+	// compression included, of which Compression encoded the bodies.
+	Serialization time.Duration
+	Compression   time.Duration
+	// Rows, Batches and Bytes count the data sent.
+	Rows    int64
+	Batches int64
+	Bytes   int64
+}
+
+// Metadata encodes the stats as gRPC metadata, for a trailer.
+func (s StreamStats) Metadata() map[string][]string {
+	ms := func(d time.Duration) []string {
+		return []string{strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64)}
+	}
+	count := func(n int64) []string {
+		return []string{strconv.FormatInt(n, 10)}
+	}
+	return map[string][]string{
+		GenerationTrailer:    ms(s.Generation),
+		SerializationTrailer: ms(s.Serialization),
+		CompressionTrailer:   ms(s.Compression),
+		RowsTrailer:          count(s.Rows),
+		BatchesTrailer:       count(s.Batches),
+		BytesTrailer:         count(s.Bytes),
+	}
+}
+
+// ParseStreamStats decodes stats from gRPC metadata. It reports false if the
+// metadata carries none.
+func ParseStreamStats(md map[string][]string) (StreamStats, bool) {
+	var s StreamStats
+	found := false
+	ms := func(key string, dst *time.Duration) {
+		if v := md[key]; len(v) > 0 {
+			if f, err := strconv.ParseFloat(v[0], 64); err == nil {
+				*dst = time.Duration(f * float64(time.Millisecond))
+				found = true
+			}
+		}
+	}
+	count := func(key string, dst *int64) {
+		if v := md[key]; len(v) > 0 {
+			if n, err := strconv.ParseInt(v[0], 10, 64); err == nil {
+				*dst = n
+				found = true
+			}
+		}
+	}
+	ms(GenerationTrailer, &s.Generation)
+	ms(SerializationTrailer, &s.Serialization)
+	ms(CompressionTrailer, &s.Compression)
+	count(RowsTrailer, &s.Rows)
+	count(BatchesTrailer, &s.Batches)
+	count(BytesTrailer, &s.Bytes)
+	return s, found
+}
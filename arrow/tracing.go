@@ -0,0 +1,7 @@
+package arrow
+
+import "go.opentelemetry.io/otel"
+
+// tracer creates the spans of the arrow package through the global tracer
+// provider, which records nothing until one is installed.
+var tracer = otel.Tracer("github.com/TFMV/ArrowLink/arrow")
@@ -5,12 +5,23 @@ import (
 	"os"
 
 	"github.com/TFMV/ArrowLink/arrow"
+	"github.com/TFMV/ArrowLink/config"
 	"github.com/TFMV/ArrowLink/grpcserver"
 	"go.uber.org/zap"
 )
 
 func main() {
-	logger, err := zap.NewProduction()
+	// Settings come from $ARROWLINK_CONFIG and ARROWLINK_* variables
+	cfg, err := config.Load("")
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	logger, err := cfg.Logger()
 	if err != nil {
 		// Here we avoid panic to make debugging easier
 		fmt.Fprintln(os.Stderr, "Failed to initialize logger:", err)
@@ -18,9 +29,29 @@ func main() {
 	}
 	defer logger.Sync()
 
-	// Create the arrow service
-	arrowService := arrow.NewArrowService()
+	sink, err := cfg.Sink()
+	if err != nil {
+		logger.Fatal("failed to configure uploads", zap.Error(err))
+	}
+	catalog, err := cfg.Catalog(sink)
+	if err != nil {
+		logger.Fatal("failed to register datasets", zap.Error(err))
+	}
+	// Register the arrow service as the sample dataset
+	if err := catalog.Register(arrow.Dataset{
+		Name:        "sample",
+		Description: "Single-row sample dataset",
+		Service:     arrow.NewArrowService(),
+	}); err != nil {
+		logger.Fatal("failed to register dataset", zap.Error(err))
+	}
+	opts, err := cfg.ServerOptions(sink)
+	if err != nil {
+		logger.Fatal("failed to configure server", zap.Error(err))
+	}
 
-	// Start the gRPC server with the arrow service injected
-	grpcserver.StartGRPCServer(":50051", logger, arrowService)
+	// Start the gRPC server with the catalog injected
+	if err := grpcserver.StartGRPCServer(cfg.Address, logger, catalog, opts...); err != nil {
+		logger.Fatal("gRPC server failed", zap.Error(err))
+	}
 }
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// APIKey is a static key and the identity it authenticates.
+type APIKey struct {
+	// Key is the secret itself. KeySHA256, the hex SHA-256 digest of the
+	// key, can be given instead to keep the secret out of config files.
+	Key       string   `json:"key,omitempty"`
+	KeySHA256 string   `json:"key_sha256,omitempty"`
+	Subject   string   `json:"subject"`
+	Groups    []string `json:"groups,omitempty"`
+}
+
+type apiKeyAuthenticator struct {
+	// identities is keyed by the SHA-256 digest of each key, so a lookup
+	// takes the same time whichever bytes of a guess are right.
+	identities map[[sha256.Size]byte]Identity
+}
+
+// NewAPIKeyAuthenticator authenticates requests whose x-api-key metadata
+// holds one of keys.
+func NewAPIKeyAuthenticator(keys []APIKey) (Authenticator, error) {
+	a := &apiKeyAuthenticator{identities: make(map[[sha256.Size]byte]Identity, len(keys))}
+	for i, k := range keys {
+		if k.Subject == "" {
+			return nil, fmt.Errorf("API key %d has no subject", i)
+		}
+
+		var digest [sha256.Size]byte
+		switch {
+		case k.Key != "" && k.KeySHA256 != "":
+			return nil, fmt.Errorf("API key for %q sets both key and key_sha256", k.Subject)
+		case k.Key != "":
+			digest = sha256.Sum256([]byte(k.Key))
+		case k.KeySHA256 != "":
+			b, err := hex.DecodeString(k.KeySHA256)
+			if err != nil || len(b) != sha256.Size {
+				return nil, fmt.Errorf("API key for %q: key_sha256 must be 64 hex digits", k.Subject)
+			}
+			copy(digest[:], b)
+		default:
+			return nil, fmt.Errorf("API key for %q has no key", k.Subject)
+		}
+
+		if _, ok := a.identities[digest]; ok {
+			return nil, fmt.Errorf("API key for %q is not unique", k.Subject)
+		}
+		a.identities[digest] = Identity{Subject: k.Subject, Groups: k.Groups, Method: "api-key"}
+	}
+	return a, nil
+}
+
+func (a *apiKeyAuthenticator) Authenticate(ctx context.Context, md metadata.MD) (Identity, error) {
+	values := md.Get(APIKeyHeader)
+	if len(values) == 0 {
+		return Identity{}, ErrNoCredentials
+	}
+	id, ok := a.identities[sha256.Sum256([]byte(values[0]))]
+	if !ok {
+		return Identity{}, errors.New("unknown API key")
+	}
+	return id, nil
+}
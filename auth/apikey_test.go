@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestAPIKeyAuthenticate(t *testing.T) {
+	digest := sha256.Sum256([]byte("hashed-secret"))
+	a, err := NewAPIKeyAuthenticator([]APIKey{
+		{Key: "s3cret", Subject: "etl", Groups: []string{"writers"}},
+		{KeySHA256: hex.EncodeToString(digest[:]), Subject: "dashboard"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		md      metadata.MD
+		want    Identity
+		wantErr error
+	}{
+		{"plain key", metadata.Pairs(APIKeyHeader, "s3cret"), Identity{Subject: "etl", Groups: []string{"writers"}, Method: "api-key"}, nil},
+		{"hashed key", metadata.Pairs(APIKeyHeader, "hashed-secret"), Identity{Subject: "dashboard", Method: "api-key"}, nil},
+		{"no key", metadata.MD{}, Identity{}, ErrNoCredentials},
+		{"bearer token only", metadata.Pairs(AuthorizationHeader, "Bearer abc"), Identity{}, ErrNoCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := a.Authenticate(context.Background(), tt.md)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(id, tt.want) {
+				t.Errorf("identity = %+v, want %+v", id, tt.want)
+			}
+		})
+	}
+
+	for _, bad := range []string{"wrong", "S3CRET", "s3cret ", "", hex.EncodeToString(digest[:])} {
+		_, err := a.Authenticate(context.Background(), metadata.Pairs(APIKeyHeader, bad))
+		if err == nil || errors.Is(err, ErrNoCredentials) {
+			t.Errorf("key %q: error = %v, want a rejection", bad, err)
+		}
+	}
+}
+
+func TestNewAPIKeyAuthenticatorErrors(t *testing.T) {
+	digest := sha256.Sum256([]byte("s3cret"))
+	tests := map[string][]APIKey{
+		"no subject":     {{Key: "s3cret"}},
+		"no key":         {{Subject: "etl"}},
+		"key and digest": {{Key: "s3cret", KeySHA256: hex.EncodeToString(digest[:]), Subject: "etl"}},
+		"short digest":   {{KeySHA256: "abcd", Subject: "etl"}},
+		"invalid digest": {{KeySHA256: "zz" + hex.EncodeToString(digest[1:]), Subject: "etl"}},
+		"duplicate key": {
+			{Key: "s3cret", Subject: "etl"},
+			{KeySHA256: hex.EncodeToString(digest[:]), Subject: "other"},
+		},
+	}
+	for name, keys := range tests {
+		if _, err := NewAPIKeyAuthenticator(keys); err == nil {
+			t.Errorf("%s: NewAPIKeyAuthenticator succeeded", name)
+		}
+	}
+}
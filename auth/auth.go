@@ -0,0 +1,134 @@
+// Package auth authenticates ArrowLink callers and authorizes the RPCs and
+// datasets they may use. Authenticators turn request metadata into an
+// Identity, which the interceptors store in the request context; a Policy
+// then decides what that identity may do.
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata keys carrying credentials.
+const (
+	APIKeyHeader        = "x-api-key"
+	AuthorizationHeader = "authorization"
+)
+
+// ErrNoCredentials is returned by an Authenticator when the request carries
+// no credentials it understands, letting the next authenticator try.
+var ErrNoCredentials = errors.New("no credentials")
+
+// Identity is an authenticated caller.
+type Identity struct {
+	// Subject names the caller, e.g. an API key's owner or a JWT's sub claim.
+	Subject string
+	// Groups lists the groups the caller belongs to.
+	Groups []string
+	// Method records how the caller authenticated: "api-key" or "jwt".
+	Method string
+}
+
+// Authenticator verifies the credentials in a request's metadata.
+type Authenticator interface {
+	// Authenticate returns the caller's identity. It returns
+	// ErrNoCredentials if md holds none of the credentials it handles, and
+	// another error if they are invalid.
+	Authenticate(ctx context.Context, md metadata.MD) (Identity, error)
+}
+
+// Chain tries each authenticator in turn. The first one that finds
+// credentials decides the outcome.
+func Chain(authenticators ...Authenticator) Authenticator {
+	return chain(authenticators)
+}
+
+type chain []Authenticator
+
+func (c chain) Authenticate(ctx context.Context, md metadata.MD) (Identity, error) {
+	for _, a := range c {
+		id, err := a.Authenticate(ctx, md)
+		if !errors.Is(err, ErrNoCredentials) {
+			return id, err
+		}
+	}
+	return Identity{}, ErrNoCredentials
+}
+
+type identityKey struct{}
+
+// NewContext returns a context carrying id.
+func NewContext(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext returns the identity stored by the interceptors, if any.
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
+
+// MethodName returns the short name of a full gRPC method name, e.g.
+// "GetArrowData" for "/dataexchange.ArrowDataService/GetArrowData".
+func MethodName(fullMethod string) string {
+	return fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+}
+
+// UnaryServerInterceptor authenticates every unary call and checks that the
+// policy lets the caller use the method. A nil policy allows every
+// authenticated caller. Dataset access is checked by the handlers.
+func UnaryServerInterceptor(a Authenticator, p *Policy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, a, p, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of
+// UnaryServerInterceptor.
+func StreamServerInterceptor(a Authenticator, p *Policy) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), a, p, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &identityStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authenticate(ctx context.Context, a Authenticator, p *Policy, fullMethod string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	id, err := a.Authenticate(ctx, md)
+	if errors.Is(err, ErrNoCredentials) {
+		return nil, status.Error(codes.Unauthenticated, "missing credentials")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid credentials: %v", err)
+	}
+
+	method := MethodName(fullMethod)
+	if !p.AllowMethod(id, method) {
+		return nil, status.Errorf(codes.PermissionDenied, "%q may not call %s", id.Subject, method)
+	}
+	return NewContext(ctx, id), nil
+}
+
+// identityStream overrides the context of a stream with one carrying the
+// caller's identity.
+type identityStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *identityStream) Context() context.Context {
+	return s.ctx
+}
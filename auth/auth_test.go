@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestChain(t *testing.T) {
+	keys, err := NewAPIKeyAuthenticator([]APIKey{{Key: "s3cret", Subject: "etl"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	failing := authenticatorFunc(func(context.Context, metadata.MD) (Identity, error) {
+		return Identity{}, errors.New("bad token")
+	})
+
+	if id, err := Chain(keys, failing).Authenticate(context.Background(), metadata.Pairs(APIKeyHeader, "s3cret")); err != nil || id.Subject != "etl" {
+		t.Errorf("first authenticator: identity %+v, error %v", id, err)
+	}
+	// The first authenticator that finds credentials decides
+	if _, err := Chain(keys, failing).Authenticate(context.Background(), metadata.Pairs(APIKeyHeader, "wrong")); err == nil {
+		t.Error("an invalid key fell through to the next authenticator")
+	}
+	if _, err := Chain(keys, failing).Authenticate(context.Background(), metadata.MD{}); err == nil || errors.Is(err, ErrNoCredentials) {
+		t.Errorf("second authenticator: error %v, want its rejection", err)
+	}
+	if _, err := Chain(keys).Authenticate(context.Background(), metadata.MD{}); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("no credentials: error %v, want ErrNoCredentials", err)
+	}
+}
+
+type authenticatorFunc func(context.Context, metadata.MD) (Identity, error)
+
+func (f authenticatorFunc) Authenticate(ctx context.Context, md metadata.MD) (Identity, error) {
+	return f(ctx, md)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	keys, err := NewAPIKeyAuthenticator([]APIKey{
+		{Key: "reader-key", Subject: "reader"},
+		{Key: "writer-key", Subject: "writer"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := &Policy{Rules: []Rule{{Subjects: []string{"reader"}, Methods: []string{"ListDatasets"}}}}
+	interceptor := UnaryServerInterceptor(keys, policy)
+	info := &grpc.UnaryServerInfo{FullMethod: "/dataexchange.ArrowDataService/ListDatasets"}
+
+	tests := []struct {
+		name string
+		md   metadata.MD
+		want codes.Code
+	}{
+		{"allowed", metadata.Pairs(APIKeyHeader, "reader-key"), codes.OK},
+		{"no credentials", metadata.MD{}, codes.Unauthenticated},
+		{"bad key", metadata.Pairs(APIKeyHeader, "guess"), codes.Unauthenticated},
+		{"method not granted", metadata.Pairs(APIKeyHeader, "writer-key"), codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			var caller Identity
+			_, err := interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				caller, _ = FromContext(ctx)
+				return nil, nil
+			})
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("code = %s, want %s (%v)", got, tt.want, err)
+			}
+			if tt.want == codes.OK && caller.Subject != "reader" {
+				t.Errorf("handler saw identity %+v", caller)
+			}
+		})
+	}
+}
+
+func TestMethodName(t *testing.T) {
+	if got := MethodName("/dataexchange.ArrowDataService/GetArrowData"); got != "GetArrowData" {
+		t.Errorf("MethodName = %q", got)
+	}
+}
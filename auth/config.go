@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Config is the file form of an authentication setup:
+//
+//	{
+//	  "api_keys": [{"key": "s3cret", "subject": "etl", "groups": ["writers"]}],
+//	  "jwt": {"jwks_file": "jwks.json", "issuer": "https://idp.example.com", "audience": "arrowlink"},
+//	  "rules": [
+//	    {"subjects": ["group:analysts"], "methods": ["GetArrowData", "DoGet", "ListDatasets", "DescribeDataset"], "datasets": ["demo"]},
+//	    {"subjects": ["group:writers"], "methods": ["SendArrowData", "DoPut"]}
+//	  ]
+//	}
+//
+// Without a "rules" entry every authenticated caller may use everything.
+type Config struct {
+	APIKeys []APIKey   `json:"api_keys"`
+	JWT     *JWTConfig `json:"jwt"`
+	Rules   []Rule     `json:"rules"`
+}
+
+// LoadConfig reads a JSON authentication config.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse auth config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Build creates the authenticator and policy the config describes. API keys
+// are tried before JWTs.
+func (c *Config) Build() (Authenticator, *Policy, error) {
+	var authenticators []Authenticator
+	if len(c.APIKeys) > 0 {
+		a, err := NewAPIKeyAuthenticator(c.APIKeys)
+		if err != nil {
+			return nil, nil, err
+		}
+		authenticators = append(authenticators, a)
+	}
+	if c.JWT != nil {
+		a, err := NewJWTAuthenticator(*c.JWT)
+		if err != nil {
+			return nil, nil, err
+		}
+		authenticators = append(authenticators, a)
+	}
+	if len(authenticators) == 0 {
+		return nil, nil, fmt.Errorf("auth config defines neither API keys nor JWT verification")
+	}
+
+	var policy *Policy
+	if c.Rules != nil {
+		policy = &Policy{Rules: c.Rules}
+		if err := policy.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return Chain(authenticators...), policy, nil
+}
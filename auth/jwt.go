@@ -0,0 +1,316 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/metadata"
+)
+
+// jwksCheckInterval bounds how often the JWKS file is checked for changes.
+const jwksCheckInterval = 30 * time.Second
+
+// JWTConfig configures verification of bearer tokens.
+type JWTConfig struct {
+	// JWKSFile is a local JSON Web Key Set holding the verification keys.
+	// It is reloaded when it changes, so keys can be rotated in place.
+	JWKSFile string `json:"jwks_file"`
+	// Issuer and Audience, when set, must match the iss and aud claims.
+	Issuer   string `json:"issuer,omitempty"`
+	Audience string `json:"audience,omitempty"`
+	// GroupsClaim names the claim listing the caller's groups, either as an
+	// array or as a space-separated string. Defaults to "groups".
+	GroupsClaim string `json:"groups_claim,omitempty"`
+	// Leeway allows for clock skew when checking exp, nbf and iat. In JSON
+	// it is a duration string such as "30s".
+	Leeway time.Duration `json:"-"`
+}
+
+func (c *JWTConfig) UnmarshalJSON(data []byte) error {
+	type plain JWTConfig
+	var v struct {
+		plain
+		Leeway string `json:"leeway"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*c = JWTConfig(v.plain)
+	if v.Leeway != "" {
+		d, err := time.ParseDuration(v.Leeway)
+		if err != nil {
+			return fmt.Errorf("invalid leeway: %w", err)
+		}
+		c.Leeway = d
+	}
+	return nil
+}
+
+// signingMethods are the algorithms accepted in tokens. HMAC is excluded
+// since a JWKS only holds public keys.
+var signingMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+type jwtAuthenticator struct {
+	cfg    JWTConfig
+	parser *jwt.Parser
+
+	mu      sync.Mutex
+	keys    *keySet
+	modTime time.Time
+	checked time.Time
+}
+
+// NewJWTAuthenticator authenticates requests carrying an
+// "authorization: Bearer <token>" header whose JWT is signed by a key in
+// the JWKS file and has not expired.
+func NewJWTAuthenticator(cfg JWTConfig) (Authenticator, error) {
+	if cfg.JWKSFile == "" {
+		return nil, errors.New("JWT authentication requires a JWKS file")
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = "groups"
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	a := &jwtAuthenticator{cfg: cfg, parser: jwt.NewParser(opts...)}
+	info, err := os.Stat(cfg.JWKSFile)
+	if err != nil {
+		return nil, err
+	}
+	if a.keys, err = loadKeySet(cfg.JWKSFile); err != nil {
+		return nil, err
+	}
+	a.modTime = info.ModTime()
+	a.checked = time.Now()
+	return a, nil
+}
+
+func (a *jwtAuthenticator) Authenticate(ctx context.Context, md metadata.MD) (Identity, error) {
+	var raw string
+	for _, v := range md.Get(AuthorizationHeader) {
+		if scheme, token, ok := strings.Cut(v, " "); ok && strings.EqualFold(scheme, "bearer") {
+			raw = strings.TrimSpace(token)
+			break
+		}
+	}
+	if raw == "" {
+		return Identity{}, ErrNoCredentials
+	}
+
+	keys := a.currentKeys()
+	claims := jwt.MapClaims{}
+	if _, err := a.parser.ParseWithClaims(raw, claims, keys.keyFunc); err != nil {
+		return Identity{}, err
+	}
+
+	sub, err := claims.GetSubject()
+	if err != nil || sub == "" {
+		return Identity{}, errors.New("token has no subject")
+	}
+	return Identity{Subject: sub, Groups: claimStrings(claims[a.cfg.GroupsClaim]), Method: "jwt"}, nil
+}
+
+// currentKeys returns the key set, reloading the JWKS file if it changed.
+// A file that fails to load leaves the previous keys in place.
+func (a *jwtAuthenticator) currentKeys() *keySet {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if time.Since(a.checked) < jwksCheckInterval {
+		return a.keys
+	}
+	a.checked = time.Now()
+	info, err := os.Stat(a.cfg.JWKSFile)
+	if err != nil || info.ModTime().Equal(a.modTime) {
+		return a.keys
+	}
+	if keys, err := loadKeySet(a.cfg.JWKSFile); err == nil {
+		a.keys = keys
+		a.modTime = info.ModTime()
+	}
+	return a.keys
+}
+
+func claimStrings(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return strings.Fields(v)
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, s := range v {
+			if s, ok := s.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// keySet holds the signature keys of a JWKS.
+type keySet struct {
+	keys []jwk
+}
+
+type jwk struct {
+	kid string
+	alg string
+	key crypto.PublicKey
+}
+
+// keyFunc picks the key named by the token's kid header, or the only key
+// if the token names none.
+func (s *keySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	for _, k := range s.keys {
+		if kid != "" && k.kid != kid {
+			continue
+		}
+		if kid == "" && len(s.keys) > 1 {
+			return nil, errors.New("token has no kid and the key set holds several keys")
+		}
+		if k.alg != "" && k.alg != token.Method.Alg() {
+			return nil, fmt.Errorf("key %q is for %s, not %s", k.kid, k.alg, token.Method.Alg())
+		}
+		return k.key, nil
+	}
+	return nil, fmt.Errorf("no key with kid %q", kid)
+}
+
+type jwkJSON struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// loadKeySet reads the RSA, EC and Ed25519 signature keys of a JWKS file.
+// Keys of other types or for encryption are skipped.
+func loadKeySet(path string) (*keySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Keys []jwkJSON `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse JWKS %s: %w", path, err)
+	}
+
+	set := &keySet{}
+	for i, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("JWKS %s: key %d: %w", path, i, err)
+		}
+		if key != nil {
+			set.keys = append(set.keys, jwk{kid: k.Kid, alg: k.Alg, key: key})
+		}
+	}
+	if len(set.keys) == 0 {
+		return nil, fmt.Errorf("JWKS %s holds no signature keys", path)
+	}
+	return set, nil
+}
+
+func (k jwkJSON) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid n: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil || !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid e")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x: %w", err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y: %w", err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid x")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, nil
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,280 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/metadata"
+)
+
+// testKey is an Ed25519 signing key and its JWKS entry.
+type testKey struct {
+	kid  string
+	priv ed25519.PrivateKey
+}
+
+func newTestKey(t *testing.T, kid string) testKey {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testKey{kid: kid, priv: priv}
+}
+
+func (k testKey) jwk() map[string]string {
+	pub := k.priv.Public().(ed25519.PublicKey)
+	return map[string]string{
+		"kty": "OKP",
+		"crv": "Ed25519",
+		"kid": k.kid,
+		"use": "sig",
+		"alg": "EdDSA",
+		"x":   base64.RawURLEncoding.EncodeToString(pub),
+	}
+}
+
+func (k testKey) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = k.kid
+	signed, err := token.SignedString(k.priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func writeJWKS(t *testing.T, path string, keys ...testKey) {
+	t.Helper()
+	var doc struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	for _, k := range keys {
+		doc.Keys = append(doc.Keys, k.jwk())
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func bearer(token string) metadata.MD {
+	return metadata.Pairs(AuthorizationHeader, "Bearer "+token)
+}
+
+// validClaims returns claims the test authenticator accepts.
+func validClaims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"sub":    "alice",
+		"iss":    "https://idp.example.com",
+		"aud":    "arrowlink",
+		"iat":    now.Unix(),
+		"exp":    now.Add(time.Hour).Unix(),
+		"groups": []string{"analysts", "writers"},
+	}
+}
+
+func newTestJWTAuthenticator(t *testing.T, keys ...testKey) (*jwtAuthenticator, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, keys...)
+	a, err := NewJWTAuthenticator(JWTConfig{
+		JWKSFile: path,
+		Issuer:   "https://idp.example.com",
+		Audience: "arrowlink",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a.(*jwtAuthenticator), path
+}
+
+func TestJWTAuthenticate(t *testing.T) {
+	key := newTestKey(t, "k1")
+	other := newTestKey(t, "k1")
+	a, _ := newTestJWTAuthenticator(t, key)
+
+	id, err := a.Authenticate(context.Background(), bearer(key.sign(t, validClaims())))
+	if err != nil {
+		t.Fatalf("valid token rejected: %v", err)
+	}
+	want := Identity{Subject: "alice", Groups: []string{"analysts", "writers"}, Method: "jwt"}
+	if !reflect.DeepEqual(id, want) {
+		t.Errorf("identity = %+v, want %+v", id, want)
+	}
+
+	spaced := validClaims()
+	spaced["groups"] = "analysts writers"
+	if id, err := a.Authenticate(context.Background(), bearer(key.sign(t, spaced))); err != nil || !reflect.DeepEqual(id.Groups, want.Groups) {
+		t.Errorf("space-separated groups: identity %+v, error %v", id, err)
+	}
+
+	with := func(edit func(jwt.MapClaims)) jwt.MapClaims {
+		c := validClaims()
+		edit(c)
+		return c
+	}
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"missing exp", key.sign(t, with(func(c jwt.MapClaims) { delete(c, "exp") }))},
+		{"expired", key.sign(t, with(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }))},
+		{"issued in the future", key.sign(t, with(func(c jwt.MapClaims) { c["iat"] = time.Now().Add(time.Hour).Unix() }))},
+		{"not yet valid", key.sign(t, with(func(c jwt.MapClaims) { c["nbf"] = time.Now().Add(time.Hour).Unix() }))},
+		{"wrong issuer", key.sign(t, with(func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }))},
+		{"wrong audience", key.sign(t, with(func(c jwt.MapClaims) { c["aud"] = "other" }))},
+		{"no subject", key.sign(t, with(func(c jwt.MapClaims) { delete(c, "sub") }))},
+		{"unknown signer", other.sign(t, validClaims())},
+		{"alg none", signNone(t, validClaims())},
+		{"HS256", signHMAC(t, key, validClaims())},
+		{"malformed", "not.a.jwt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := a.Authenticate(context.Background(), bearer(tt.token))
+			if err == nil {
+				t.Fatal("token accepted")
+			}
+			if errors.Is(err, ErrNoCredentials) {
+				t.Errorf("error %v lets another authenticator try", err)
+			}
+		})
+	}
+}
+
+func signNone(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodNone, claims)
+	token.Header["kid"] = "k1"
+	signed, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// signHMAC signs with the public key bytes as the HMAC secret, the classic
+// algorithm confusion attack on verifiers that trust the token's alg.
+func signHMAC(t *testing.T, key testKey, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = key.kid
+	signed, err := token.SignedString([]byte(key.priv.Public().(ed25519.PublicKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestJWTNoCredentials(t *testing.T) {
+	key := newTestKey(t, "k1")
+	a, _ := newTestJWTAuthenticator(t, key)
+	for _, md := range []metadata.MD{
+		{},
+		metadata.Pairs(AuthorizationHeader, "Basic dXNlcjpwYXNz"),
+		metadata.Pairs(AuthorizationHeader, "Bearer "),
+		metadata.Pairs(APIKeyHeader, "s3cret"),
+	} {
+		if _, err := a.Authenticate(context.Background(), md); !errors.Is(err, ErrNoCredentials) {
+			t.Errorf("Authenticate(%v) = %v, want ErrNoCredentials", md, err)
+		}
+	}
+}
+
+func TestJWTKeySelection(t *testing.T) {
+	k1, k2 := newTestKey(t, "k1"), newTestKey(t, "k2")
+	a, _ := newTestJWTAuthenticator(t, k1, k2)
+	if _, err := a.Authenticate(context.Background(), bearer(k2.sign(t, validClaims()))); err != nil {
+		t.Errorf("token signed by the second key rejected: %v", err)
+	}
+
+	// Without a kid, a token is only accepted when the set holds one key
+	unnamed := jwt.NewWithClaims(jwt.SigningMethodEdDSA, validClaims())
+	signed, err := unnamed.SignedString(k1.priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Authenticate(context.Background(), bearer(signed)); err == nil {
+		t.Error("token without kid accepted by a set of two keys")
+	}
+	single, _ := newTestJWTAuthenticator(t, k1)
+	if _, err := single.Authenticate(context.Background(), bearer(signed)); err != nil {
+		t.Errorf("token without kid rejected by a set of one key: %v", err)
+	}
+}
+
+func TestJWTReload(t *testing.T) {
+	old, rotated := newTestKey(t, "old"), newTestKey(t, "new")
+	a, path := newTestJWTAuthenticator(t, old)
+	token := rotated.sign(t, validClaims())
+	if _, err := a.Authenticate(context.Background(), bearer(token)); err == nil {
+		t.Fatal("token signed by an unknown key accepted")
+	}
+
+	writeJWKS(t, path, rotated)
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+	// The file is only checked once per jwksCheckInterval
+	if _, err := a.Authenticate(context.Background(), bearer(token)); err == nil {
+		t.Fatal("key set reloaded before the check interval")
+	}
+	a.mu.Lock()
+	a.checked = time.Now().Add(-jwksCheckInterval)
+	a.mu.Unlock()
+	if _, err := a.Authenticate(context.Background(), bearer(token)); err != nil {
+		t.Fatalf("token signed by the rotated key rejected: %v", err)
+	}
+	if _, err := a.Authenticate(context.Background(), bearer(old.sign(t, validClaims()))); err == nil {
+		t.Error("token signed by the removed key accepted")
+	}
+
+	// A broken file leaves the previous keys in place
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	later := future.Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	a.mu.Lock()
+	a.checked = time.Now().Add(-jwksCheckInterval)
+	a.mu.Unlock()
+	if _, err := a.Authenticate(context.Background(), bearer(token)); err != nil {
+		t.Errorf("broken JWKS file dropped the loaded keys: %v", err)
+	}
+}
+
+func TestNewJWTAuthenticatorErrors(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.json")
+	if err := os.WriteFile(empty, []byte(`{"keys": [{"kty": "oct", "k": "c2VjcmV0"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for name, cfg := range map[string]JWTConfig{
+		"no file":         {},
+		"missing file":    {JWKSFile: filepath.Join(dir, "missing.json")},
+		"no signing keys": {JWKSFile: empty},
+	} {
+		if _, err := NewJWTAuthenticator(cfg); err == nil {
+			t.Errorf("%s: NewJWTAuthenticator succeeded", name)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Policy maps identities to the RPCs and datasets they may use. Anything
+// not granted by a rule is denied. A nil Policy grants every authenticated
+// identity everything.
+type Policy struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule grants its subjects the use of some RPCs on some datasets.
+type Rule struct {
+	// Subjects lists identity subjects, "group:<name>" entries matching
+	// members of a group, or "*" for every authenticated identity.
+	Subjects []string `json:"subjects"`
+	// Methods lists RPC names such as "GetArrowData" or "DoGet", or "*"
+	// for every RPC.
+	Methods []string `json:"methods"`
+	// Datasets lists the dataset names the methods may be used on, or "*"
+	// for all of them. RPCs that do not read a dataset, such as
+	// SendArrowData, only need the method to be granted.
+	Datasets []string `json:"datasets"`
+}
+
+// Validate reports rules that can never match.
+func (p *Policy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	for i, r := range p.Rules {
+		if len(r.Subjects) == 0 {
+			return fmt.Errorf("rule %d has no subjects", i)
+		}
+		if len(r.Methods) == 0 {
+			return fmt.Errorf("rule %d has no methods", i)
+		}
+	}
+	return nil
+}
+
+// AllowMethod reports whether id may call method on at least some dataset.
+func (p *Policy) AllowMethod(id Identity, method string) bool {
+	if p == nil {
+		return true
+	}
+	for _, r := range p.Rules {
+		if r.matchesSubject(id) && matches(r.Methods, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowDataset reports whether id may call method on dataset.
+func (p *Policy) AllowDataset(id Identity, method, dataset string) bool {
+	if p == nil {
+		return true
+	}
+	for _, r := range p.Rules {
+		if r.matchesSubject(id) && matches(r.Methods, method) && matches(r.Datasets, dataset) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesSubject reports whether id is among the rule's subjects. A
+// "group:" entry only matches group members, never a subject that happens
+// to be spelled the same way.
+func (r Rule) matchesSubject(id Identity) bool {
+	for _, s := range r.Subjects {
+		if group, ok := strings.CutPrefix(s, "group:"); ok {
+			if slices.Contains(id.Groups, group) {
+				return true
+			}
+			continue
+		}
+		if s == "*" || s == id.Subject {
+			return true
+		}
+	}
+	return false
+}
+
+func matches(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if p == "*" || p == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package auth
+
+import "testing"
+
+func TestPolicy(t *testing.T) {
+	policy := &Policy{Rules: []Rule{
+		{Subjects: []string{"group:analysts"}, Methods: []string{"GetArrowData", "DoGet"}, Datasets: []string{"demo", "sales"}},
+		{Subjects: []string{"etl"}, Methods: []string{"SendArrowData"}},
+		{Subjects: []string{"admin"}, Methods: []string{"*"}, Datasets: []string{"*"}},
+		{Subjects: []string{"*"}, Methods: []string{"ListDatasets"}, Datasets: []string{"*"}},
+	}}
+	if err := policy.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	analyst := Identity{Subject: "alice", Groups: []string{"staff", "analysts"}}
+	etl := Identity{Subject: "etl"}
+	admin := Identity{Subject: "admin"}
+	stranger := Identity{Subject: "bob", Groups: []string{"staff"}}
+	impostor := Identity{Subject: "group:analysts"}
+
+	tests := []struct {
+		name    string
+		id      Identity
+		method  string
+		dataset string
+		// method and dataset are the expected AllowMethod and
+		// AllowDataset results.
+		allowMethod, allowDataset bool
+	}{
+		{"group member on a granted dataset", analyst, "GetArrowData", "demo", true, true},
+		{"group member on another granted method", analyst, "DoGet", "sales", true, true},
+		{"group member on another dataset", analyst, "GetArrowData", "payroll", true, false},
+		{"group member on another method", analyst, "SendArrowData", "", false, false},
+		{"subject without datasets", etl, "SendArrowData", "", true, false},
+		{"subject on another method", etl, "GetArrowData", "demo", false, false},
+		{"wildcard methods and datasets", admin, "DescribeDataset", "payroll", true, true},
+		{"wildcard subject", stranger, "ListDatasets", "demo", true, true},
+		{"wildcard subject on another method", stranger, "GetArrowData", "demo", false, false},
+		{"subject named like a group", impostor, "GetArrowData", "demo", false, false},
+		{"method names are exact", analyst, "getarrowdata", "demo", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.AllowMethod(tt.id, tt.method); got != tt.allowMethod {
+				t.Errorf("AllowMethod = %t, want %t", got, tt.allowMethod)
+			}
+			if got := policy.AllowDataset(tt.id, tt.method, tt.dataset); got != tt.allowDataset {
+				t.Errorf("AllowDataset = %t, want %t", got, tt.allowDataset)
+			}
+		})
+	}
+}
+
+func TestNilPolicy(t *testing.T) {
+	var policy *Policy
+	id := Identity{Subject: "anyone"}
+	if !policy.AllowMethod(id, "SendArrowData") || !policy.AllowDataset(id, "GetArrowData", "payroll") {
+		t.Error("a nil policy must allow every authenticated caller")
+	}
+	if err := policy.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
+
+func TestEmptyPolicy(t *testing.T) {
+	policy := &Policy{}
+	if policy.AllowMethod(Identity{Subject: "anyone"}, "GetArrowData") {
+		t.Error("a policy without rules must deny everything")
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	for name, rule := range map[string]Rule{
+		"no subjects": {Methods: []string{"*"}},
+		"no methods":  {Subjects: []string{"*"}},
+	} {
+		if err := (&Policy{Rules: []Rule{rule}}).Validate(); err == nil {
+			t.Errorf("%s: Validate succeeded", name)
+		}
+	}
+}
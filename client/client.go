@@ -0,0 +1,325 @@
+// Package client is a Go client for ArrowLink servers. It hides the
+// dataexchange stubs and payload framing: reads return an
+// array.RecordReader, and writes take arrow.Records directly.
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/TFMV/ArrowLink/arrow"
+	pb "github.com/TFMV/ArrowLink/proto/dataexchange"
+	arrowgo "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultMaxMessageSize is the largest message a client accepts unless
+// WithMaxMessageSize says otherwise. It matches the Python client.
+const DefaultMaxMessageSize = 50 * 1024 * 1024
+
+// Client is a connection to an ArrowLink server. It is safe for concurrent use.
+type Client struct {
+	conn        *grpc.ClientConn
+	stub        pb.ArrowDataServiceClient
+	mem         memory.Allocator
+	compression arrow.Codec
+	retry       retryPolicy
+}
+
+type config struct {
+	tls         *tls.Config
+	keepalive   *keepalive.ClientParameters
+	maxMsgSize  int
+	dialOpts    []grpc.DialOption
+	mem         memory.Allocator
+	compression arrow.Codec
+	retry       retryPolicy
+	callCreds   map[string]string
+	err         error
+}
+
+// Option configures a Client.
+type Option func(*config)
+
+// WithTLS connects over TLS with the given configuration. Without a TLS
+// option the connection is unencrypted. Options applied afterwards, such as
+// WithCACert, modify cfg.
+func WithTLS(cfg *tls.Config) Option {
+	return func(c *config) {
+		c.tls = cfg
+	}
+}
+
+// WithCACert connects over TLS, trusting the PEM-encoded CA certificate in
+// path, such as the certs/ca.crt file used by the Python client.
+func WithCACert(path string) Option {
+	return func(c *config) {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			c.err = fmt.Errorf("read CA certificate: %w", err)
+			return
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			c.err = fmt.Errorf("no certificates found in %s", path)
+			return
+		}
+		c.tlsConfig().RootCAs = pool
+	}
+}
+
+// WithClientCert connects over TLS, presenting the PEM-encoded certificate
+// and key to servers that require mutual TLS.
+func WithClientCert(certFile, keyFile string) Option {
+	return func(c *config) {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			c.err = fmt.Errorf("load client certificate: %w", err)
+			return
+		}
+		cfg := c.tlsConfig()
+		cfg.Certificates = append(cfg.Certificates, cert)
+	}
+}
+
+func (c *config) tlsConfig() *tls.Config {
+	if c.tls == nil {
+		c.tls = &tls.Config{}
+	}
+	return c.tls
+}
+
+// WithAPIKey authenticates every call with a static API key, sent in the
+// x-api-key metadata.
+func WithAPIKey(key string) Option {
+	return func(c *config) {
+		c.credential("x-api-key", key)
+	}
+}
+
+// WithBearerToken authenticates every call with a JWT, sent in the
+// authorization metadata. Credentials are sent over unencrypted connections
+// too, so use TLS outside local development.
+func WithBearerToken(token string) Option {
+	return func(c *config) {
+		c.credential("authorization", "Bearer "+token)
+	}
+}
+
+func (c *config) credential(key, value string) {
+	if c.callCreds == nil {
+		c.callCreds = make(map[string]string)
+	}
+	c.callCreds[key] = value
+}
+
+// metadataCredentials attaches fixed metadata to every call.
+type metadataCredentials map[string]string
+
+func (m metadataCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return m, nil
+}
+
+func (m metadataCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// WithKeepalive pings the server after interval without activity and drops
+// the connection if no reply arrives within timeout. The server's keepalive
+// enforcement policy must allow the interval.
+func WithKeepalive(interval, timeout time.Duration) Option {
+	return func(c *config) {
+		c.keepalive = &keepalive.ClientParameters{
+			Time:                interval,
+			Timeout:             timeout,
+			PermitWithoutStream: true,
+		}
+	}
+}
+
+// WithRetry retries calls that fail with Unavailable up to attempts times in
+// total, waiting backoff before the first retry and doubling the wait after
+// each one. Streams are only retried until their first batch arrives.
+func WithRetry(attempts int, backoff time.Duration) Option {
+	return func(c *config) {
+		c.retry = retryPolicy{attempts: attempts, backoff: backoff}
+	}
+}
+
+// WithMaxMessageSize sets the largest message the client sends or receives.
+func WithMaxMessageSize(bytes int) Option {
+	return func(c *config) {
+		c.maxMsgSize = bytes
+	}
+}
+
+// WithCompression sets the codec used for uploaded batches and requested for
+// downloads when a Request does not choose one.
+func WithCompression(codec arrow.Codec) Option {
+	return func(c *config) {
+		c.compression = codec
+	}
+}
+
+// WithAllocator sets the allocator used to decode record batches.
+func WithAllocator(mem memory.Allocator) Option {
+	return func(c *config) {
+		c.mem = mem
+	}
+}
+
+// WithDialOptions passes additional options to grpc.NewClient.
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(c *config) {
+		c.dialOpts = append(c.dialOpts, opts...)
+	}
+}
+
+// Dial creates a client for the server at target. The connection is
+// established lazily by the first call. Calls are traced through the global
+// OpenTelemetry tracer provider and propagator, such as those installed by
+// tracing.Setup, which send the trace context of each call's ctx along.
+func Dial(target string, opts ...Option) (*Client, error) {
+	cfg := config{
+		maxMsgSize: DefaultMaxMessageSize,
+		mem:        memory.NewGoAllocator(),
+		retry:      retryPolicy{attempts: 1},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.err != nil {
+		return nil, cfg.err
+	}
+
+	creds := insecure.NewCredentials()
+	if cfg.tls != nil {
+		creds = credentials.NewTLS(cfg.tls)
+	}
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(cfg.maxMsgSize),
+			grpc.MaxCallSendMsgSize(cfg.maxMsgSize),
+		),
+	}
+	if cfg.callCreds != nil {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(metadataCredentials(cfg.callCreds)))
+	}
+	if cfg.keepalive != nil {
+		dialOpts = append(dialOpts, grpc.WithKeepaliveParams(*cfg.keepalive))
+	}
+	dialOpts = append(dialOpts, cfg.dialOpts...)
+
+	conn, err := grpc.NewClient(target, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		conn:        conn,
+		stub:        pb.NewArrowDataServiceClient(conn),
+		mem:         cfg.mem,
+		compression: cfg.compression,
+		retry:       cfg.retry,
+	}, nil
+}
+
+// Close closes the connection. Readers and writers still open fail.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// DatasetInfo describes a dataset served by the server.
+type DatasetInfo struct {
+	Name          string
+	Description   string
+	Schema        *arrowgo.Schema
+	EstimatedRows int64 // -1 if unknown
+	Metadata      map[string]string
+}
+
+// ListDatasets describes every dataset the server hosts.
+func (c *Client) ListDatasets(ctx context.Context) ([]DatasetInfo, error) {
+	var list *pb.DatasetList
+	err := c.retry.do(ctx, func() (err error) {
+		list, err = c.stub.ListDatasets(ctx, &pb.Empty{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]DatasetInfo, 0, len(list.GetDatasets()))
+	for _, ds := range list.GetDatasets() {
+		info, err := c.datasetInfo(ds)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// DescribeDataset describes a single dataset. An empty name selects the
+// server's default dataset.
+func (c *Client) DescribeDataset(ctx context.Context, name string) (DatasetInfo, error) {
+	var ds *pb.DatasetInfo
+	err := c.retry.do(ctx, func() (err error) {
+		ds, err = c.stub.DescribeDataset(ctx, &pb.DatasetRequest{Dataset: name})
+		return err
+	})
+	if err != nil {
+		return DatasetInfo{}, err
+	}
+	return c.datasetInfo(ds)
+}
+
+func (c *Client) datasetInfo(ds *pb.DatasetInfo) (DatasetInfo, error) {
+	schema, err := flight.DeserializeSchema(ds.GetSchema(), c.mem)
+	if err != nil {
+		return DatasetInfo{}, fmt.Errorf("dataset %q: invalid schema: %w", ds.GetName(), err)
+	}
+	return DatasetInfo{
+		Name:          ds.GetName(),
+		Description:   ds.GetDescription(),
+		Schema:        schema,
+		EstimatedRows: ds.GetEstimatedRows(),
+		Metadata:      ds.GetMetadata(),
+	}, nil
+}
+
+type retryPolicy struct {
+	attempts int
+	backoff  time.Duration
+}
+
+// do runs fn until it succeeds, fails with an error other than Unavailable,
+// or the attempts are used up.
+func (p retryPolicy) do(ctx context.Context, fn func() error) error {
+	wait := p.backoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.attempts || status.Code(err) != codes.Unavailable {
+			return err
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		wait *= 2
+	}
+}
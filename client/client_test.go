@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TFMV/ArrowLink/arrow"
+	"github.com/TFMV/ArrowLink/grpcserver"
+	arrowgo "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetArrowDataNoRows(t *testing.T) {
+	c := startServer(t, map[string]arrow.ArrowService{"demo": arrow.NewDemoArrowService(100)})
+	reader, err := c.GetArrowData(context.Background(), Request{Dataset: "demo", Columns: []string{"id", "value"}, Filter: "id < 0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Release()
+
+	if got := reader.Schema(); got.NumFields() != 2 || got.Field(0).Name != "id" || got.Field(1).Name != "value" {
+		t.Errorf("schema = %s, want the id and value columns", got)
+	}
+	if reader.Next() {
+		t.Errorf("received a batch of %d rows", reader.Record().NumRows())
+	}
+	if err := reader.Err(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStats(t *testing.T) {
+	c := startServer(t, map[string]arrow.ArrowService{"colors": colorService{}})
+	reader, err := c.GetArrowData(context.Background(), Request{Dataset: "colors"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Release()
+
+	var rows int64
+	for reader.Next() {
+		if _, ok := Stats(reader); ok {
+			t.Fatal("Stats reported before the end of the stream")
+		}
+		rows += reader.Record().NumRows()
+	}
+	if err := reader.Err(); err != nil {
+		t.Fatal(err)
+	}
+	stats, ok := Stats(reader)
+	if !ok {
+		t.Fatal("Stats reported nothing after the end of the stream")
+	}
+	if stats.Rows != rows || stats.Batches != int64(len(colorBatches)) || stats.Bytes == 0 {
+		t.Errorf("stats = %+v, want %d rows in %d batches", stats, rows, len(colorBatches))
+	}
+}
+
+// blockingService sends one batch, then blocks until its read is cancelled,
+// which it reports on cancelled.
+type blockingService struct {
+	cancelled chan struct{}
+}
+
+func (s blockingService) GetData(ctx context.Context, opts arrow.ReadOptions) (array.RecordReader, error) {
+	sent := false
+	next := func() (arrowgo.Record, error) {
+		if !sent {
+			sent = true
+			records := colorRecords(memory.NewGoAllocator())
+			for _, rec := range records[1:] {
+				rec.Release()
+			}
+			return records[0], nil
+		}
+		<-ctx.Done()
+		close(s.cancelled)
+		return nil, ctx.Err()
+	}
+	r := &funcReader{schema: colorsSchema, next: next}
+	r.refs.Store(1)
+	return r, nil
+}
+
+// funcReader is a record reader whose batches come from next.
+type funcReader struct {
+	refs   atomic.Int64
+	schema *arrowgo.Schema
+	next   func() (arrowgo.Record, error)
+	cur    arrowgo.Record
+	err    error
+}
+
+func (r *funcReader) Retain()                 { r.refs.Add(1) }
+func (r *funcReader) Schema() *arrowgo.Schema { return r.schema }
+func (r *funcReader) Record() arrowgo.Record  { return r.cur }
+func (r *funcReader) Err() error              { return r.err }
+
+func (r *funcReader) Release() {
+	if r.refs.Add(-1) == 0 && r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+}
+
+func (r *funcReader) Next() bool {
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+	r.cur, r.err = r.next()
+	return r.cur != nil
+}
+
+func TestReleaseCancelsStream(t *testing.T) {
+	svc := blockingService{cancelled: make(chan struct{})}
+	c := startServer(t, map[string]arrow.ArrowService{"slow": svc})
+	reader, err := c.GetArrowData(context.Background(), Request{Dataset: "slow"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reader.Next() {
+		t.Fatalf("no first batch: %v", reader.Err())
+	}
+	reader.Release()
+
+	select {
+	case <-svc.cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("releasing the reader did not cancel the server's read")
+	}
+}
+
+// failFirst fails the first n calls with Unavailable and counts every call.
+func failFirst(n int64, calls *atomic.Int64) grpcserver.Option {
+	fail := func() error {
+		if calls.Add(1) <= n {
+			return status.Error(codes.Unavailable, "warming up")
+		}
+		return nil
+	}
+	return grpcserver.WithStreamInterceptors(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := fail(); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	})
+}
+
+func TestRetryUnavailable(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		attempts int
+		code     codes.Code
+		calls    int64
+	}{
+		{"retried", 3, codes.OK, 3},
+		{"attempts used up", 2, codes.Unavailable, 2},
+		{"no retries", 1, codes.Unavailable, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls atomic.Int64
+			c := startServer(t, map[string]arrow.ArrowService{"colors": colorService{}}, failFirst(2, &calls))
+			c.retry = retryPolicy{attempts: tc.attempts, backoff: time.Millisecond}
+
+			reader, err := c.GetArrowData(context.Background(), Request{Dataset: "colors"})
+			if status.Code(err) != tc.code {
+				t.Fatalf("GetArrowData error = %v, want %v", err, tc.code)
+			}
+			if err == nil {
+				checkColors(t, reader, 0)
+				reader.Release()
+			}
+			if got := calls.Load(); got != tc.calls {
+				t.Errorf("server saw %d calls, want %d", got, tc.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/TFMV/ArrowLink/arrow"
+	pb "github.com/TFMV/ArrowLink/proto/dataexchange"
+	arrowgo "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"google.golang.org/grpc/metadata"
+)
+
+// Request selects the data returned by GetArrowData.
+type Request struct {
+	// Dataset names the dataset to read. Empty selects the server's default.
+	Dataset string
+	// Columns projects the result onto these columns. Empty returns all.
+	Columns []string
+	// Filter is a server-side row filter such as "value > 50".
+	Filter string
+	// Compression requests a codec for the batch bodies. Empty uses the
+	// client's default, and failing that the server's.
+	Compression arrow.Codec
+	// CompressionLevel requests a codec level. Only zero, the codec default,
+	// is currently accepted by the server.
+	CompressionLevel int
+}
+
+// GetArrowData streams a dataset from the server. It returns once the schema
+// has arrived; the batches are received as the reader advances. Releasing
+// the reader before the end cancels the stream. The client asks for
+// FRAMING_MESSAGES, so that the schema is sent once, and falls back to
+// decoding each payload on its own for servers that do not support it.
+func (c *Client) GetArrowData(ctx context.Context, req Request) (array.RecordReader, error) {
+	codec := req.Compression
+	if codec == "" {
+		codec = c.compression
+	}
+	msg := &pb.DataRequest{
+		Dataset:          req.Dataset,
+		Columns:          req.Columns,
+		Filter:           req.Filter,
+		Compression:      compressionEnum(codec),
+		CompressionLevel: int32(req.CompressionLevel),
+		Framing:          pb.Framing_FRAMING_MESSAGES,
+	}
+
+	var r *streamReader
+	err := c.retry.do(ctx, func() error {
+		streamCtx, cancel := context.WithCancel(ctx)
+		stream, err := c.stub.GetArrowData(streamCtx, msg)
+		if err != nil {
+			cancel()
+			return err
+		}
+		r = &streamReader{refCount: 1, recv: stream.Recv, trailer: stream.Trailer, cancel: cancel, mem: c.mem}
+		header, err := stream.Header()
+		if err != nil {
+			r.Release()
+			return err
+		}
+		if v := header.Get(arrow.FramingHeader); len(v) > 0 && v[0] == arrow.FramingMessages {
+			r.framed, err = ipc.NewReader(&payloadReader{recv: r.receive}, ipc.WithAllocator(c.mem))
+			if err != nil {
+				err = r.recvError(err)
+			} else {
+				r.schema = r.framed.Schema()
+			}
+		} else {
+			err = r.fill()
+		}
+		if err != nil {
+			r.Release()
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func compressionEnum(codec arrow.Codec) pb.Compression {
+	switch codec {
+	case arrow.CodecNone:
+		return pb.Compression_COMPRESSION_NONE
+	case arrow.CodecLZ4Frame:
+		return pb.Compression_COMPRESSION_LZ4_FRAME
+	case arrow.CodecZstd:
+		return pb.Compression_COMPRESSION_ZSTD
+	default:
+		return pb.Compression_COMPRESSION_UNSPECIFIED
+	}
+}
+
+// Stats returns the server's account of a GetArrowData stream: the time it
+// spent producing, serializing and compressing batches and the data it sent.
+// It reports false until the reader has been read to the end, or if the
+// server sent no stats.
+func Stats(reader array.RecordReader) (arrow.StreamStats, bool) {
+	r, ok := reader.(*streamReader)
+	if !ok || !r.hasStats {
+		return arrow.StreamStats{}, false
+	}
+	return r.stats, true
+}
+
+// NewFramedReader reassembles the payloads of a GetArrowData stream
+// requested with FRAMING_MESSAGES into a single IPC reader. It blocks until
+// the schema has arrived.
+func NewFramedReader(stream pb.ArrowDataService_GetArrowDataClient, opts ...ipc.Option) (*ipc.Reader, error) {
+	return ipc.NewReader(&payloadReader{recv: stream.Recv}, opts...)
+}
+
+// payloadReader reads the payloads of a stream back to back.
+type payloadReader struct {
+	recv func() (*pb.ArrowData, error)
+	buf  []byte
+}
+
+func (r *payloadReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		msg, err := r.recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = msg.GetPayload()
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// streamReader decodes the ArrowData messages of a GetArrowData stream.
+// Unless the stream is framed as IPC messages, each payload is a
+// self-contained IPC stream holding one or more batches.
+type streamReader struct {
+	refCount int64
+	recv     func() (*pb.ArrowData, error)
+	trailer  func() metadata.MD
+	cancel   context.CancelFunc
+	mem      memory.Allocator
+	// framed decodes the whole stream when it is framed as IPC messages.
+	framed  *ipc.Reader
+	recvErr error
+
+	schema   *arrowgo.Schema
+	pending  []arrowgo.Record
+	cur      arrowgo.Record
+	err      error
+	done     bool
+	stats    arrow.StreamStats
+	hasStats bool
+}
+
+func (r *streamReader) Retain() {
+	atomic.AddInt64(&r.refCount, 1)
+}
+
+func (r *streamReader) Release() {
+	if atomic.AddInt64(&r.refCount, -1) == 0 {
+		if r.cur != nil {
+			r.cur.Release()
+			r.cur = nil
+		}
+		for _, rec := range r.pending {
+			rec.Release()
+		}
+		r.pending = nil
+		if r.framed != nil {
+			r.framed.Release()
+		}
+		r.cancel()
+	}
+}
+
+func (r *streamReader) Schema() *arrowgo.Schema { return r.schema }
+func (r *streamReader) Record() arrowgo.Record  { return r.cur }
+func (r *streamReader) Err() error              { return r.err }
+
+func (r *streamReader) Next() bool {
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+	for len(r.pending) == 0 {
+		if r.done || r.err != nil {
+			return false
+		}
+		if err := r.fill(); err != nil {
+			r.err = err
+			r.cancel()
+			return false
+		}
+	}
+	r.cur, r.pending = r.pending[0], r.pending[1:]
+	return true
+}
+
+// receive receives one message, reading the stats from the trailer once the
+// stream has ended.
+func (r *streamReader) receive() (*pb.ArrowData, error) {
+	msg, err := r.recv()
+	if err != nil {
+		r.recvErr = err
+		r.stats, r.hasStats = arrow.ParseStreamStats(r.trailer())
+	}
+	return msg, err
+}
+
+// recvError prefers the error that ended the stream, which carries the
+// server's status, over err, the decoding error it caused.
+func (r *streamReader) recvError(err error) error {
+	if r.recvErr != nil && !errors.Is(r.recvErr, io.EOF) {
+		return r.recvErr
+	}
+	return fmt.Errorf("invalid payload: %w", err)
+}
+
+// fillFramed queues the next batch of a stream framed as IPC messages.
+func (r *streamReader) fillFramed() error {
+	for r.framed.Next() {
+		if rec := r.framed.Record(); rec.NumRows() > 0 {
+			rec.Retain()
+			r.pending = append(r.pending, rec)
+			return nil
+		}
+	}
+	r.done = true
+	if err := r.framed.Err(); err != nil {
+		return r.recvError(err)
+	}
+	return nil
+}
+
+// fill receives one message and queues its non-empty batches. The server
+// sends a single empty batch when no rows match, which only sets the schema.
+func (r *streamReader) fill() error {
+	if r.framed != nil {
+		return r.fillFramed()
+	}
+	msg, err := r.receive()
+	if errors.Is(err, io.EOF) {
+		r.done = true
+		if r.schema == nil {
+			return fmt.Errorf("server closed the stream without sending a schema")
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	reader, err := ipc.NewReader(bytes.NewReader(msg.GetPayload()), ipc.WithAllocator(r.mem))
+	if err != nil {
+		return fmt.Errorf("invalid payload: %w", err)
+	}
+	defer reader.Release()
+
+	if r.schema == nil {
+		r.schema = reader.Schema()
+	} else if !r.schema.Equal(reader.Schema()) {
+		return fmt.Errorf("payload schema %s does not match stream schema %s", reader.Schema(), r.schema)
+	}
+	for reader.Next() {
+		if rec := reader.Record(); rec.NumRows() > 0 {
+			rec.Retain()
+			r.pending = append(r.pending, rec)
+		}
+	}
+	if err := reader.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid payload: %w", err)
+	}
+	return nil
+}
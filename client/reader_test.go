@@ -0,0 +1,269 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/TFMV/ArrowLink/arrow"
+	"github.com/TFMV/ArrowLink/grpcserver"
+	pb "github.com/TFMV/ArrowLink/proto/dataexchange"
+	arrowgo "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startServer serves services over an in-memory connection until the test
+// ends and returns a client of it.
+func startServer(t *testing.T, services map[string]arrow.ArrowService, opts ...grpcserver.Option) *Client {
+	t.Helper()
+	cat := arrow.NewCatalog()
+	for name, svc := range services {
+		if err := cat.Register(arrow.Dataset{Name: name, Service: svc}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lis := bufconn.Listen(1 << 20)
+	s := grpcserver.NewServer(zap.NewNop(), cat, append([]grpcserver.Option{grpcserver.WithListener(lis)}, opts...)...)
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		s.Stop(ctx)
+	})
+	return dialServer(t, lis)
+}
+
+// dialServer returns a client of the server listening on lis.
+func dialServer(t *testing.T, lis *bufconn.Listener, opts ...Option) *Client {
+	t.Helper()
+	dialer := grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	})
+	c, err := Dial("passthrough:///bufconn", append(opts, WithDialOptions(dialer))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+var colorsSchema = arrowgo.NewSchema([]arrowgo.Field{
+	{Name: "id", Type: arrowgo.PrimitiveTypes.Int64},
+	{Name: "color", Type: &arrowgo.DictionaryType{IndexType: arrowgo.PrimitiveTypes.Int32, ValueType: arrowgo.BinaryTypes.String}},
+}, nil)
+
+// colorBatches holds the colors of each batch of colorService. The second
+// batch extends the first one's dictionary and the third replaces it.
+var colorBatches = [][]string{
+	{"red", "green", "red"},
+	{"green", "blue"},
+	{"yellow", "yellow", "yellow", "yellow"},
+	{"red"},
+}
+
+// colorService serves colorBatches, each with its own dictionary. It can
+// resume streams, so the server sends resume tokens.
+type colorService struct{}
+
+func (colorService) Snapshot() string { return "colors" }
+
+func (colorService) GetData(ctx context.Context, opts arrow.ReadOptions) (array.RecordReader, error) {
+	records := colorRecords(memory.NewGoAllocator())[opts.StartBatch:]
+	defer func() {
+		for _, rec := range records {
+			rec.Release()
+		}
+	}()
+	return array.NewRecordReader(colorsSchema, records)
+}
+
+func colorRecords(mem memory.Allocator) []arrowgo.Record {
+	var records []arrowgo.Record
+	id := int64(0)
+	for _, colors := range colorBatches {
+		b := array.NewRecordBuilder(mem, colorsSchema)
+		for _, color := range colors {
+			b.Field(0).(*array.Int64Builder).Append(id)
+			b.Field(1).(*array.BinaryDictionaryBuilder).AppendString(color)
+			id++
+		}
+		records = append(records, b.NewRecord())
+		b.Release()
+	}
+	return records
+}
+
+// checkColors checks that reader returns colorBatches from batch first on.
+func checkColors(t *testing.T, reader interface {
+	Next() bool
+	Record() arrowgo.Record
+	Err() error
+}, first int) {
+	t.Helper()
+	want := colorRecords(memory.NewGoAllocator())
+	defer func() {
+		for _, rec := range want {
+			rec.Release()
+		}
+	}()
+	want = want[first:]
+	n := 0
+	for ; reader.Next(); n++ {
+		if n >= len(want) {
+			t.Fatalf("received more than %d batches", len(want))
+		}
+		if got := reader.Record(); !array.RecordEqual(got, want[n]) {
+			t.Errorf("batch %d = %v, want %v", first+n, got, want[n])
+		}
+	}
+	if err := reader.Err(); err != nil && !errors.Is(err, io.EOF) {
+		t.Fatal(err)
+	}
+	if n != len(want) {
+		t.Errorf("received %d batches, want %d", n, len(want))
+	}
+}
+
+func TestGetArrowDataFramedDictionaries(t *testing.T) {
+	c := startServer(t, map[string]arrow.ArrowService{"colors": colorService{}})
+	reader, err := c.GetArrowData(context.Background(), Request{Dataset: "colors"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Release()
+	if !reader.Schema().Equal(colorsSchema) {
+		t.Errorf("schema = %s", reader.Schema())
+	}
+	checkColors(t, reader, 0)
+}
+
+// recvFunc adapts a function to pb.ArrowDataService_GetArrowDataClient for
+// NewFramedReader.
+type recvFunc func() (*pb.ArrowData, error)
+
+func (f recvFunc) Recv() (*pb.ArrowData, error) { return f() }
+func (recvFunc) Header() (metadata.MD, error)   { return nil, nil }
+func (recvFunc) Trailer() metadata.MD           { return nil }
+func (recvFunc) CloseSend() error               { return nil }
+func (recvFunc) Context() context.Context       { return context.Background() }
+func (recvFunc) SendMsg(any) error              { return nil }
+func (recvFunc) RecvMsg(any) error              { return nil }
+
+// replay returns the messages one by one, then io.EOF.
+func replay(messages []*pb.ArrowData) recvFunc {
+	return func() (*pb.ArrowData, error) {
+		if len(messages) == 0 {
+			return nil, io.EOF
+		}
+		msg := messages[0]
+		messages = messages[1:]
+		return msg, nil
+	}
+}
+
+func TestFramedResumeTokens(t *testing.T) {
+	c := startServer(t, map[string]arrow.ArrowService{"colors": colorService{}})
+	receive := func(token []byte) []*pb.ArrowData {
+		t.Helper()
+		stream, err := c.stub.GetArrowData(context.Background(), &pb.DataRequest{
+			Dataset:     "colors",
+			Framing:     pb.Framing_FRAMING_MESSAGES,
+			ResumeToken: token,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var messages []*pb.ArrowData
+		for {
+			msg, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return messages
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			messages = append(messages, msg)
+		}
+	}
+
+	// The schema and the first dictionaries come ahead of the first batch,
+	// in a payload of their own; every batch after that is one payload
+	messages := receive(nil)
+	if len(messages) != len(colorBatches)+1 {
+		t.Fatalf("received %d payloads, want %d", len(messages), len(colorBatches)+1)
+	}
+	if len(messages[0].ResumeToken) != 0 {
+		t.Error("the payload before the first batch has a resume token")
+	}
+	for i, msg := range messages[1:] {
+		if len(msg.ResumeToken) == 0 {
+			t.Errorf("the payload completing batch %d has no resume token", i)
+		}
+	}
+
+	framed, err := NewFramedReader(replay(messages))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkColors(t, framed, 0)
+	framed.Release()
+
+	// Resuming after the second batch sends the schema and the dictionaries
+	// again, so that the rest decodes on its own
+	framed, err = NewFramedReader(replay(receive(messages[2].ResumeToken)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkColors(t, framed, 2)
+	framed.Release()
+}
+
+// withoutFraming makes the server behave like one that predates
+// FRAMING_MESSAGES: it ignores the requested framing and sends no framing
+// header.
+func withoutFraming() grpcserver.Option {
+	return grpcserver.WithStreamInterceptors(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, unframedStream{ss})
+	})
+}
+
+type unframedStream struct {
+	grpc.ServerStream
+}
+
+func (s unframedStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if req, ok := m.(*pb.DataRequest); ok {
+		req.Framing = pb.Framing_FRAMING_STREAM
+	}
+	return err
+}
+
+func (s unframedStream) SetHeader(md metadata.MD) error {
+	md = md.Copy()
+	delete(md, arrow.FramingHeader)
+	return s.ServerStream.SetHeader(md)
+}
+
+func TestGetArrowDataWithoutFraming(t *testing.T) {
+	c := startServer(t, map[string]arrow.ArrowService{"colors": colorService{}}, withoutFraming())
+	reader, err := c.GetArrowData(context.Background(), Request{Dataset: "colors"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Release()
+	if r := reader.(*streamReader); r.framed != nil {
+		t.Fatal("the client decodes the stream as framed")
+	}
+	checkColors(t, reader, 0)
+}
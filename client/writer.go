@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+
+	"github.com/TFMV/ArrowLink/arrow"
+	pb "github.com/TFMV/ArrowLink/proto/dataexchange"
+	arrowgo "github.com/apache/arrow-go/v18/arrow"
+)
+
+// Ack summarizes an upload, as reported by the server.
+type Ack struct {
+	Message string
+	Rows    int64
+	Batches int64
+	Bytes   int64
+	// RejectedPayloads counts batches the server could not decode. Errors
+	// describes each of them. A batch that does not match the server's
+	// schema fails Close with InvalidArgument instead.
+	RejectedPayloads int64
+	Errors           []string
+}
+
+// Writer uploads record batches through SendArrowData. Writes are not
+// retried, since the server may already have stored earlier batches.
+type Writer struct {
+	stream pb.ArrowDataService_SendArrowDataClient
+	cancel context.CancelFunc
+	codec  arrow.Codec
+}
+
+// NewWriter opens an upload stream. Every batch written is sent as one
+// message; Close ends the stream and returns the server's summary.
+func (c *Client) NewWriter(ctx context.Context) (*Writer, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.stub.SendArrowData(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &Writer{stream: stream, cancel: cancel, codec: c.compression}, nil
+}
+
+// Write sends a single record batch. The record may be released once Write
+// returns.
+func (w *Writer) Write(record arrowgo.Record) error {
+	payload, err := arrow.SerializeRecord(record, w.codec)
+	if err != nil {
+		return err
+	}
+	return w.stream.Send(&pb.ArrowData{Payload: payload})
+}
+
+// Close finishes the upload and waits for the server's acknowledgement.
+func (w *Writer) Close() (*Ack, error) {
+	defer w.cancel()
+	ack, err := w.stream.CloseAndRecv()
+	if err != nil {
+		return nil, err
+	}
+	return &Ack{
+		Message:          ack.GetMessage(),
+		Rows:             ack.GetRows(),
+		Batches:          ack.GetBatches(),
+		Bytes:            ack.GetBytes(),
+		RejectedPayloads: ack.GetRejectedPayloads(),
+		Errors:           ack.GetErrors(),
+	}, nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -15,14 +16,31 @@ func main() {
 	minSize := flag.Int("min", 1000, "Minimum number of rows")
 	maxSize := flag.Int("max", 1000000, "Maximum number of rows")
 	steps := flag.Int("steps", 5, "Number of steps between min and max")
+	batchSize := flag.Int("batch", arrow.DefaultBatchSize, "Maximum rows per record batch")
+	compression := flag.String("compression", "none", "IPC body compression: none, lz4 or zstd")
+	schema := flag.String("schema", "", "JSON or YAML spec of the generated columns (default: the demo columns)")
+	workers := flag.Int("workers", 0, "Goroutines generating batches (0: GOMAXPROCS)")
+	seed := flag.Int64("seed", 1, "Seed for the generated data, so that runs are comparable")
 	flag.Parse()
 
 	logger, _ := zap.NewDevelopment()
 	defer logger.Sync()
 
+	codec, err := arrow.ParseCodec(*compression)
+	if err != nil {
+		log.Fatal(err)
+	}
+	spec := arrow.DefaultGeneratorSpec()
+	if *schema != "" {
+		if spec, err = arrow.LoadGeneratorSpec(*schema); err != nil {
+			log.Fatal(err)
+		}
+	}
+	spec.Seed = seed
+
 	fmt.Println("ArrowLink Benchmark")
 	fmt.Println("==================")
-	fmt.Printf("Testing data sizes from %d to %d rows in %d steps\n\n", *minSize, *maxSize, *steps)
+	fmt.Printf("Testing data sizes from %d to %d rows in %d steps (compression: %s)\n\n", *minSize, *maxSize, *steps, codec)
 	fmt.Println("Rows\tSize (KB)\tGen Time (ms)\tSer Time (ms)\tTotal Time (ms)")
 	fmt.Println("----\t---------\t------------\t------------\t--------------")
 
@@ -34,24 +52,45 @@ func main() {
 
 	for size := *minSize; size <= *maxSize; size += stepSize {
 		// Create service with specific size
-		service := arrow.NewDemoArrowService(size)
+		service, err := arrow.NewDemoArrowServiceFromSpec(spec, size)
+		if err != nil {
+			log.Fatal(err)
+		}
 
-		// Measure time
+		// Measure time, serializing each batch as the server would
+		var dataBytes int
+		var genTime, serTime time.Duration
 		start := time.Now()
-		data, err := service.GetData()
+		reader, err := service.GetData(context.Background(), arrow.ReadOptions{BatchSize: *batchSize, Parallelism: *workers})
 		if err != nil {
 			log.Fatalf("Error generating data: %v", err)
 		}
+		for {
+			startGen := time.Now()
+			ok := reader.Next()
+			genTime += time.Since(startGen)
+			if !ok {
+				break
+			}
+			startSer := time.Now()
+			payload, err := arrow.SerializeRecord(reader.Record(), codec)
+			if err != nil {
+				log.Fatalf("Error serializing data: %v", err)
+			}
+			serTime += time.Since(startSer)
+			dataBytes += len(payload)
+		}
+		if err := reader.Err(); err != nil {
+			log.Fatalf("Error generating data: %v", err)
+		}
+		reader.Release()
 		elapsed := time.Since(start)
 
-		// Get metrics from the benchmark service
-		metrics := service.(*arrow.DemoArrowService).GetMetrics()
-
 		fmt.Printf("%d\t%d\t\t%.2f\t\t%.2f\t\t%.2f\n",
 			size,
-			len(data)/1024,
-			float64(metrics.GenerationTime)/float64(time.Millisecond),
-			float64(metrics.SerializationTime)/float64(time.Millisecond),
+			dataBytes/1024,
+			float64(genTime)/float64(time.Millisecond),
+			float64(serTime)/float64(time.Millisecond),
 			float64(elapsed)/float64(time.Millisecond))
 	}
 }
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Generate a local CA with server and client certificates",
+	Long: `Generate a self-signed CA plus server and client key pairs for local
+development and testing. The output directory holds:
+
+  ca.crt, ca.key          the CA; pass ca.crt to the Python client's --cert
+                          and to the server's --tls-client-ca
+  server.crt, server.key  for the server's --tls-cert and --tls-key
+  client.crt, client.key  for clients of servers that require mutual TLS
+
+Keys are ECDSA P-256 in PKCS #8 PEM files readable only by their owner.`,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, _ := cmd.Flags().GetString("out")
+		hosts, _ := cmd.Flags().GetStringSlice("hosts")
+		clientName, _ := cmd.Flags().GetString("client-name")
+		caValidity, _ := cmd.Flags().GetDuration("ca-validity")
+		validity, _ := cmd.Flags().GetDuration("validity")
+		force, _ := cmd.Flags().GetBool("force")
+
+		if len(hosts) == 0 {
+			return fmt.Errorf("at least one host is required for the server certificate")
+		}
+		if validity > caValidity {
+			return fmt.Errorf("certificate validity %s exceeds the CA validity %s", validity, caValidity)
+		}
+		if !force {
+			for _, name := range []string{"ca", "server", "client"} {
+				for _, ext := range []string{".crt", ".key"} {
+					path := filepath.Join(out, name+ext)
+					if _, err := os.Stat(path); err == nil {
+						return fmt.Errorf("%s already exists; use --force to overwrite", path)
+					}
+				}
+			}
+		}
+		if err := os.MkdirAll(out, 0o755); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		ca, err := newCertificate(certRequest{
+			commonName: "ArrowLink Local CA",
+			notBefore:  now,
+			notAfter:   now.Add(caValidity),
+			isCA:       true,
+		}, nil)
+		if err != nil {
+			return err
+		}
+		server, err := newCertificate(certRequest{
+			commonName: hosts[0],
+			hosts:      hosts,
+			notBefore:  now,
+			notAfter:   now.Add(validity),
+			usage:      x509.ExtKeyUsageServerAuth,
+		}, ca)
+		if err != nil {
+			return err
+		}
+		client, err := newCertificate(certRequest{
+			commonName: clientName,
+			notBefore:  now,
+			notAfter:   now.Add(validity),
+			usage:      x509.ExtKeyUsageClientAuth,
+		}, ca)
+		if err != nil {
+			return err
+		}
+
+		for name, c := range map[string]*keyPair{"ca": ca, "server": server, "client": client} {
+			if err := c.write(out, name); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Wrote CA, server and client certificates to %s\n", out)
+		return nil
+	},
+}
+
+type certRequest struct {
+	commonName string
+	hosts      []string
+	notBefore  time.Time
+	notAfter   time.Time
+	isCA       bool
+	usage      x509.ExtKeyUsage
+}
+
+type keyPair struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+// newCertificate creates a key pair for req, signed by issuer or self-signed
+// when issuer is nil.
+func newCertificate(req certRequest, issuer *keyPair) (*keyPair, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{Organization: []string{"ArrowLink"}, CommonName: req.commonName},
+		// Allow for clock skew between the machines using the certificate.
+		NotBefore: req.notBefore.Add(-5 * time.Minute),
+		NotAfter:  req.notAfter,
+		KeyUsage:  x509.KeyUsageDigitalSignature,
+	}
+	if req.isCA {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.MaxPathLenZero = true
+		template.KeyUsage |= x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	} else {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{req.usage}
+	}
+	for _, h := range req.hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, h)
+		}
+	}
+
+	parent, signer := template, crypto.Signer(key)
+	if issuer != nil {
+		parent, signer = issuer.cert, issuer.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), signer)
+	if err != nil {
+		return nil, fmt.Errorf("create %s certificate: %w", req.commonName, err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &keyPair{cert: cert, key: key}, nil
+}
+
+// write stores the pair as <name>.crt and <name>.key in dir.
+func (p *keyPair) write(dir, name string) error {
+	keyDER, err := x509.MarshalPKCS8PrivateKey(p.key)
+	if err != nil {
+		return err
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: p.cert.Raw})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+
+	if err := os.WriteFile(filepath.Join(dir, name+".crt"), certPEM, 0o644); err != nil {
+		return err
+	}
+	// WriteFile keeps the permissions of an existing file, so an old key is
+	// removed first to make sure the new one is only readable by its owner.
+	keyPath := filepath.Join(dir, name+".key")
+	if err := os.Remove(keyPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return os.WriteFile(keyPath, keyPEM, 0o600)
+}
+
+func init() {
+	rootCmd.AddCommand(certsCmd)
+
+	certsCmd.Flags().StringP("out", "o", "certs", "Directory to write the certificates to")
+	certsCmd.Flags().StringSlice("hosts", []string{"localhost", "127.0.0.1", "::1"}, "DNS names and IP addresses the server certificate is valid for")
+	certsCmd.Flags().String("client-name", "arrowlink-client", "Common name of the client certificate")
+	certsCmd.Flags().Duration("ca-validity", 10*365*24*time.Hour, "Lifetime of the CA certificate")
+	certsCmd.Flags().Duration("validity", 365*24*time.Hour, "Lifetime of the server and client certificates")
+	certsCmd.Flags().Bool("force", false, "Overwrite existing files")
+}
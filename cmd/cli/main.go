@@ -1,11 +1,18 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"net/http"
 	"os"
 
 	"github.com/TFMV/ArrowLink/arrow"
+	"github.com/TFMV/ArrowLink/config"
 	"github.com/TFMV/ArrowLink/grpcserver"
+	"github.com/TFMV/ArrowLink/metrics"
+	"github.com/TFMV/ArrowLink/tracing"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 )
@@ -18,15 +25,61 @@ var rootCmd = &cobra.Command{
 var serverCmd = &cobra.Command{
 	Use:   "server",
 	Short: "Start the ArrowLink gRPC server",
+	Long: `Start the ArrowLink gRPC server.
+
+Settings come from a YAML or TOML file (--config or $ARROWLINK_CONFIG), then
+ARROWLINK_* environment variables, then flags, each overriding the last.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		port, _ := cmd.Flags().GetString("port")
-		rows, _ := cmd.Flags().GetInt("rows")
+		cfg, err := config.LoadFlags(cmd.Flags())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
 
-		logger, _ := zap.NewProduction()
+		logger, err := cfg.Logger()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to initialize logger:", err)
+			os.Exit(1)
+		}
 		defer logger.Sync()
 
-		arrowService := arrow.NewDemoArrowService(rows)
-		grpcserver.StartGRPCServer(":"+port, logger, arrowService)
+		shutdownTracing, err := tracing.Setup(cmd.Context(), cfg.TracingConfig())
+		if err != nil {
+			logger.Fatal("failed to set up tracing", zap.Error(err))
+		}
+		defer func() {
+			if err := shutdownTracing(context.Background()); err != nil {
+				logger.Warn("failed to flush traces", zap.Error(err))
+			}
+		}()
+
+		sink, err := cfg.Sink()
+		if err != nil {
+			logger.Fatal("failed to configure uploads", zap.Error(err))
+		}
+		catalog, err := cfg.Catalog(sink)
+		if err != nil {
+			logger.Fatal("failed to register datasets", zap.Error(err))
+		}
+		opts, err := cfg.ServerOptions(sink)
+		if err != nil {
+			logger.Fatal("failed to configure server", zap.Error(err))
+		}
+		if cfg.MetricsAddress != "" {
+			m := metrics.New()
+			opts = append(opts, grpcserver.WithMetrics(m))
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", m.Handler())
+			go func() {
+				logger.Info("metrics endpoint is running", zap.String("address", cfg.MetricsAddress))
+				if err := http.ListenAndServe(cfg.MetricsAddress, mux); err != nil {
+					logger.Fatal("failed to serve metrics", zap.Error(err))
+				}
+			}()
+		}
+		if err := grpcserver.StartGRPCServer(cfg.Address, logger, catalog, opts...); err != nil {
+			logger.Fatal("gRPC server failed", zap.Error(err))
+		}
 	},
 }
 
@@ -36,9 +89,26 @@ var generateCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		rows, _ := cmd.Flags().GetInt("rows")
 		output, _ := cmd.Flags().GetString("output")
+		schema, _ := cmd.Flags().GetString("schema")
 
-		arrowService := arrow.NewDemoArrowService(rows)
-		data, err := arrowService.GetData()
+		spec := arrow.DefaultGeneratorSpec()
+		if schema != "" {
+			var err error
+			if spec, err = arrow.LoadGeneratorSpec(schema); err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading schema: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if cmd.Flags().Changed("seed") {
+			seed, _ := cmd.Flags().GetInt64("seed")
+			spec.Seed = &seed
+		}
+		arrowService, err := arrow.NewDemoArrowServiceFromSpec(spec, rows)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating data: %v\n", err)
+			os.Exit(1)
+		}
+		data, err := collectIPC(cmd.Context(), arrowService, rows)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error generating data: %v\n", err)
 			os.Exit(1)
@@ -66,15 +136,42 @@ var generateCmd = &cobra.Command{
 	},
 }
 
+// collectIPC reads every batch from the service into a single IPC stream.
+func collectIPC(ctx context.Context, service arrow.ArrowService, batchSize int) ([]byte, error) {
+	reader, err := service.GetData(ctx, arrow.ReadOptions{BatchSize: batchSize})
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Release()
+
+	var buf bytes.Buffer
+	writer := ipc.NewWriter(&buf, ipc.WithSchema(reader.Schema()))
+	for reader.Next() {
+		if err := writer.Write(reader.Record()); err != nil {
+			writer.Close()
+			return nil, err
+		}
+	}
+	if err := reader.Err(); err != nil {
+		writer.Close()
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 func init() {
 	rootCmd.AddCommand(serverCmd)
 	rootCmd.AddCommand(generateCmd)
 
-	serverCmd.Flags().StringP("port", "p", "50051", "Port to listen on")
-	serverCmd.Flags().IntP("rows", "r", 1000, "Number of rows to generate")
+	config.RegisterFlags(serverCmd.Flags())
 
 	generateCmd.Flags().IntP("rows", "r", 100, "Number of rows to generate")
 	generateCmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
+	generateCmd.Flags().String("schema", "", "JSON or YAML spec of the columns and their distributions")
+	generateCmd.Flags().Int64("seed", 0, "Seed for reproducible data (default: the schema's seed, or random)")
 }
 
 func main() {
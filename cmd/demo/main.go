@@ -1,37 +1,70 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"os"
 	"os/exec"
 	"runtime"
 	"time"
 
-	"github.com/TFMV/ArrowLink/arrow"
+	"github.com/TFMV/ArrowLink/config"
 	"github.com/TFMV/ArrowLink/grpcserver"
 	"go.uber.org/zap"
 )
 
 func main() {
-	logger, _ := zap.NewDevelopment()
-	defer logger.Sync()
-
 	// Parse command line flags
-	dataSize := flag.Int("size", 1000, "Number of rows to generate")
+	dataSize := flag.Int("size", config.DefaultDemoRows, "Number of rows to generate")
 	benchmark := flag.Bool("benchmark", false, "Run performance benchmark")
 	visualize := flag.Bool("visualize", false, "Generate visualization of data")
 	insecure := flag.Bool("insecure", true, "Use insecure connection (no TLS)")
 	flag.Parse()
 
-	// Create the arrow service with configurable data size
-	arrowService := arrow.NewDemoArrowService(*dataSize)
+	// Settings come from ARROWLINK_* variables, then flags; the Python
+	// client expects the server on the default address
+	cfg := config.Default()
+	cfg.Log = config.Log{Level: "debug", Format: config.LogFormatConsole}
+	err := cfg.LoadEnv()
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "size" {
+			cfg.Datasets.DemoRows = *dataSize
+		}
+	})
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
-	// Start the gRPC server in a goroutine
-	go grpcserver.StartGRPCServer(":50051", logger, arrowService)
-	logger.Info("Server started on :50051")
+	logger, err := cfg.Logger()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to initialize logger:", err)
+		os.Exit(1)
+	}
+	defer logger.Sync()
 
-	// Wait for server to start
-	time.Sleep(1 * time.Second)
+	sink, err := cfg.Sink()
+	if err != nil {
+		logger.Fatal("failed to configure uploads", zap.Error(err))
+	}
+	catalog, err := cfg.Catalog(sink)
+	if err != nil {
+		logger.Fatal("failed to register datasets", zap.Error(err))
+	}
+	opts, err := cfg.ServerOptions(sink)
+	if err != nil {
+		logger.Fatal("failed to configure server", zap.Error(err))
+	}
+
+	// Start the gRPC server; Start returns once it accepts connections
+	srv := grpcserver.NewServer(logger, catalog, append(opts, grpcserver.WithAddress(grpcserver.DefaultAddress))...)
+	if err := srv.Start(context.Background()); err != nil {
+		logger.Fatal("failed to start gRPC server", zap.Error(err))
+	}
 
 	// Run the Python client
 	pythonPath := "python"
@@ -56,8 +89,15 @@ func main() {
 	cmd.Stderr = os.Stderr
 
 	logger.Info("Running Python client...")
-	if err := cmd.Run(); err != nil {
-		logger.Error("Failed to run Python client", zap.Error(err))
+	clientErr := cmd.Run()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Stop(ctx); err != nil {
+		logger.Warn("failed to stop gRPC server", zap.Error(err))
+	}
+	if clientErr != nil {
+		logger.Error("Failed to run Python client", zap.Error(clientErr))
 		os.Exit(1)
 	}
 }
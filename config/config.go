@@ -0,0 +1,546 @@
+// Package config holds the settings of the ArrowLink server and loads them
+// from a YAML or TOML file, ARROWLINK_* environment variables and command
+// line flags. Later sources take precedence: defaults are overridden by the
+// file, the file by the environment and the environment by flags.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/TFMV/ArrowLink/arrow"
+	"github.com/TFMV/ArrowLink/auth"
+	"github.com/TFMV/ArrowLink/grpcserver"
+	"github.com/TFMV/ArrowLink/tracing"
+	arrowgo "github.com/apache/arrow-go/v18/arrow"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+)
+
+// Log formats accepted in Log.Format.
+const (
+	LogFormatJSON    = "json"
+	LogFormatConsole = "console"
+)
+
+// DefaultDemoRows is the size of the demo dataset unless configured otherwise.
+const DefaultDemoRows = 1000
+
+// Sinks accepted in Uploads.Sink.
+const (
+	// SinkDiscard validates uploads and drops them.
+	SinkDiscard = "discard"
+	// SinkMemory keeps uploads in memory and serves them as the
+	// UploadsDataset dataset.
+	SinkMemory = "memory"
+)
+
+// UploadsDataset names the dataset that serves uploads kept by SinkMemory.
+const UploadsDataset = "uploads"
+
+// Config is the configuration of an ArrowLink server. In YAML:
+//
+//	address: ":50051"
+//	log: {level: info, format: json}
+//	tls: {cert_file: certs/server.crt, key_file: certs/server.key}
+//	limits: {max_recv_message_size: 67108864}
+//	keepalive: {time: 2m, timeout: 20s, min_time: 30s}
+//	compression: zstd
+//	uploads: {schema: uploads.yaml, sink: memory}
+//	datasets:
+//	  demo_rows: 1000
+//	  demo_schema: demo.yaml
+//	  sources:
+//	    - {name: trips, path: data/trips.parquet}
+type Config struct {
+	// Address is the TCP address the server listens on.
+	Address string `yaml:"address" toml:"address"`
+	// Compression is the IPC body codec used when a client does not ask for
+	// one: none, lz4 or zstd.
+	Compression string `yaml:"compression" toml:"compression"`
+	// BatchSize is the maximum number of rows per streamed record batch.
+	BatchSize int `yaml:"batch_size" toml:"batch_size"`
+	// HealthInterval is how often datasets are checked; zero checks them
+	// only at startup.
+	HealthInterval time.Duration `yaml:"health_interval" toml:"health_interval"`
+	// MetricsAddress serves Prometheus metrics at /metrics when set.
+	MetricsAddress string `yaml:"metrics_address" toml:"metrics_address"`
+	// AuthConfig is a JSON authentication config; see auth.Config.
+	AuthConfig string `yaml:"auth_config" toml:"auth_config"`
+
+	TLS       TLS       `yaml:"tls" toml:"tls"`
+	Log       Log       `yaml:"log" toml:"log"`
+	Limits    Limits    `yaml:"limits" toml:"limits"`
+	Keepalive Keepalive `yaml:"keepalive" toml:"keepalive"`
+	Uploads   Uploads   `yaml:"uploads" toml:"uploads"`
+	Datasets  Datasets  `yaml:"datasets" toml:"datasets"`
+	Tracing   Tracing   `yaml:"tracing" toml:"tracing"`
+}
+
+// TLS configures the server certificate; see grpcserver.TLSConfig.
+type TLS struct {
+	CertFile          string `yaml:"cert_file" toml:"cert_file"`
+	KeyFile           string `yaml:"key_file" toml:"key_file"`
+	ClientCAFile      string `yaml:"client_ca_file" toml:"client_ca_file"`
+	RequireClientCert bool   `yaml:"require_client_cert" toml:"require_client_cert"`
+}
+
+// Log configures the server's logger.
+type Log struct {
+	// Level is debug, info, warn or error.
+	Level string `yaml:"level" toml:"level"`
+	// Format is LogFormatJSON or LogFormatConsole.
+	Format string `yaml:"format" toml:"format"`
+}
+
+// Limits bounds the size of gRPC messages, in bytes. Zero keeps gRPC's
+// defaults.
+type Limits struct {
+	MaxRecvMessageSize int `yaml:"max_recv_message_size" toml:"max_recv_message_size"`
+	MaxSendMessageSize int `yaml:"max_send_message_size" toml:"max_send_message_size"`
+}
+
+// Keepalive configures HTTP/2 keepalive pings; see grpcserver.KeepaliveConfig.
+type Keepalive struct {
+	Time                time.Duration `yaml:"time" toml:"time"`
+	Timeout             time.Duration `yaml:"timeout" toml:"timeout"`
+	MinTime             time.Duration `yaml:"min_time" toml:"min_time"`
+	PermitWithoutStream bool          `yaml:"permit_without_stream" toml:"permit_without_stream"`
+}
+
+// Uploads configures what happens to the batches clients upload through
+// SendArrowData and Flight DoPut.
+type Uploads struct {
+	// Schema is a JSON or YAML file in the format of Datasets.DemoSchema
+	// whose column names and types every upload must match; the other
+	// column settings are ignored. By default any schema is accepted.
+	Schema string `yaml:"schema" toml:"schema"`
+	// Sink is SinkDiscard or SinkMemory, which requires Schema.
+	Sink string `yaml:"sink" toml:"sink"`
+}
+
+// Datasets lists the datasets the server publishes.
+type Datasets struct {
+	// DemoRows is the size of the synthetic "demo" dataset; zero leaves it
+	// out.
+	DemoRows int `yaml:"demo_rows" toml:"demo_rows"`
+	// DemoSchema is a JSON or YAML arrow.GeneratorSpec describing the demo
+	// dataset's columns. By default it has the columns of
+	// arrow.DefaultGeneratorSpec.
+	DemoSchema string `yaml:"demo_schema" toml:"demo_schema"`
+	// Sources are CSV or Parquet files or directories.
+	Sources []Source `yaml:"sources" toml:"sources"`
+}
+
+// Source is a CSV or Parquet file or directory served as a dataset.
+type Source struct {
+	// Name defaults to the base name of Path, minus any extension.
+	Name string `yaml:"name" toml:"name"`
+	Path string `yaml:"path" toml:"path"`
+	// Schema is a JSON or YAML file in the format of Datasets.DemoSchema
+	// giving the column names and types; the other column settings are
+	// ignored. By default Parquet files provide their own schema and CSV
+	// types are inferred from the first rows.
+	Schema string `yaml:"schema" toml:"schema"`
+}
+
+// ParseSource parses a source given as [name=]path.
+func ParseSource(spec string) Source {
+	name, path, ok := strings.Cut(spec, "=")
+	if !ok {
+		return Source{Path: spec}
+	}
+	return Source{Name: name, Path: path}
+}
+
+// DatasetName returns the name the source is published under.
+func (s Source) DatasetName() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return strings.TrimSuffix(filepath.Base(s.Path), filepath.Ext(s.Path))
+}
+
+// Tracing configures OpenTelemetry tracing; see tracing.Config.
+type Tracing struct {
+	Exporter    string  `yaml:"exporter" toml:"exporter"`
+	Endpoint    string  `yaml:"endpoint" toml:"endpoint"`
+	Insecure    bool    `yaml:"insecure" toml:"insecure"`
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio"`
+}
+
+// Default returns the configuration used when nothing overrides it.
+func Default() *Config {
+	return &Config{
+		Address:        grpcserver.DefaultAddress,
+		Compression:    string(arrow.CodecNone),
+		BatchSize:      arrow.DefaultBatchSize,
+		HealthInterval: grpcserver.DefaultHealthCheckInterval,
+		Log:            Log{Level: "info", Format: LogFormatJSON},
+		Uploads:        Uploads{Sink: SinkDiscard},
+		Datasets:       Datasets{DemoRows: DefaultDemoRows},
+		Tracing: Tracing{
+			Exporter: tracing.ExporterNone,
+			Endpoint: tracing.DefaultOTLPEndpoint,
+			Insecure: true,
+		},
+	}
+}
+
+// Load returns the default configuration overridden by the file at path, if
+// any, and then by the environment. An empty path falls back to
+// $ARROWLINK_CONFIG. The result is not validated.
+func Load(path string) (*Config, error) {
+	if path == "" {
+		path = os.Getenv(EnvPrefix + "CONFIG")
+	}
+	c := Default()
+	if path != "" {
+		if err := c.LoadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.LoadEnv(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// LoadFile overrides c with the settings in a YAML (.yaml, .yml, .json) or
+// TOML (.toml) file. Unknown keys are errors, so that typos do not go
+// unnoticed.
+func (c *Config) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml", ".json":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("parse config %s: %w", path, err)
+		}
+	case ".toml":
+		md, err := toml.Decode(string(data), c)
+		if err != nil {
+			return fmt.Errorf("parse config %s: %w", path, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("parse config %s: unknown key %q", path, undecoded[0].String())
+		}
+	default:
+		return fmt.Errorf("config %s: unsupported format %q (want .yaml, .yml, .json or .toml)", path, ext)
+	}
+	return nil
+}
+
+// Validate checks every setting and reports all problems at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(key string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+	checkFile := func(key, path string) {
+		if path == "" {
+			return
+		}
+		if _, err := os.Stat(path); err != nil {
+			check(key, err)
+		}
+	}
+
+	if _, _, err := net.SplitHostPort(c.Address); err != nil {
+		check("address", err)
+	}
+	_, err := arrow.ParseCodec(c.Compression)
+	check("compression", err)
+	if c.BatchSize <= 0 {
+		check("batch_size", fmt.Errorf("must be positive, got %d", c.BatchSize))
+	}
+	if c.HealthInterval < 0 {
+		check("health_interval", fmt.Errorf("must not be negative, got %s", c.HealthInterval))
+	}
+	if c.MetricsAddress != "" {
+		if _, _, err := net.SplitHostPort(c.MetricsAddress); err != nil {
+			check("metrics_address", err)
+		}
+	}
+	checkFile("auth_config", c.AuthConfig)
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		check("tls", errors.New("cert_file and key_file must be set together"))
+	}
+	if c.TLS.RequireClientCert && c.TLS.ClientCAFile == "" {
+		check("tls.require_client_cert", errors.New("requires client_ca_file"))
+	}
+	if c.TLS.ClientCAFile != "" && c.TLS.CertFile == "" {
+		check("tls.client_ca_file", errors.New("requires cert_file and key_file"))
+	}
+	checkFile("tls.cert_file", c.TLS.CertFile)
+	checkFile("tls.key_file", c.TLS.KeyFile)
+	checkFile("tls.client_ca_file", c.TLS.ClientCAFile)
+
+	if _, err := zapcore.ParseLevel(c.Log.Level); err != nil {
+		check("log.level", fmt.Errorf("unknown level %q (want debug, info, warn or error)", c.Log.Level))
+	}
+	if c.Log.Format != LogFormatJSON && c.Log.Format != LogFormatConsole {
+		check("log.format", fmt.Errorf("unknown format %q (want %s or %s)", c.Log.Format, LogFormatJSON, LogFormatConsole))
+	}
+
+	if c.Limits.MaxRecvMessageSize < 0 {
+		check("limits.max_recv_message_size", fmt.Errorf("must not be negative, got %d", c.Limits.MaxRecvMessageSize))
+	}
+	if c.Limits.MaxSendMessageSize < 0 {
+		check("limits.max_send_message_size", fmt.Errorf("must not be negative, got %d", c.Limits.MaxSendMessageSize))
+	}
+
+	for _, d := range []struct {
+		key   string
+		value time.Duration
+	}{
+		{"keepalive.time", c.Keepalive.Time},
+		{"keepalive.timeout", c.Keepalive.Timeout},
+		{"keepalive.min_time", c.Keepalive.MinTime},
+	} {
+		if d.value < 0 {
+			check(d.key, fmt.Errorf("must not be negative, got %s", d.value))
+		}
+	}
+
+	if c.Uploads.Schema != "" {
+		_, err := arrow.LoadGeneratorSpec(c.Uploads.Schema)
+		check("uploads.schema", err)
+	}
+	switch c.Uploads.Sink {
+	case SinkDiscard:
+	case SinkMemory:
+		if c.Uploads.Schema == "" {
+			check("uploads.sink", fmt.Errorf("%s requires uploads.schema", SinkMemory))
+		}
+	default:
+		check("uploads.sink", fmt.Errorf("unknown sink %q (want %s or %s)", c.Uploads.Sink, SinkDiscard, SinkMemory))
+	}
+
+	if c.Datasets.DemoRows < 0 {
+		check("datasets.demo_rows", fmt.Errorf("must not be negative, got %d", c.Datasets.DemoRows))
+	}
+	if c.Datasets.DemoSchema != "" {
+		_, err := arrow.LoadGeneratorSpec(c.Datasets.DemoSchema)
+		check("datasets.demo_schema", err)
+	}
+	names := make(map[string]bool)
+	if c.Datasets.DemoRows > 0 {
+		names["demo"] = true
+	}
+	if c.Uploads.Sink == SinkMemory {
+		names[UploadsDataset] = true
+	}
+	for i, src := range c.Datasets.Sources {
+		key := fmt.Sprintf("datasets.sources[%d]", i)
+		if src.Path == "" {
+			check(key, errors.New("path is required"))
+			continue
+		}
+		if _, err := arrow.DetectFormat(src.Path); err != nil {
+			check(key, err)
+		}
+		if src.Schema != "" {
+			_, err := arrow.LoadGeneratorSpec(src.Schema)
+			check(key+".schema", err)
+		}
+		name := src.DatasetName()
+		if name == "" {
+			check(key, fmt.Errorf("cannot derive a dataset name from %q", src.Path))
+		} else if names[name] {
+			check(key, fmt.Errorf("duplicate dataset name %q", name))
+		}
+		names[name] = true
+	}
+	if len(names) == 0 {
+		check("datasets", errors.New("no datasets configured"))
+	}
+
+	switch c.Tracing.Exporter {
+	case "", tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP:
+	default:
+		check("tracing.exporter", fmt.Errorf("unknown exporter %q (want %s, %s or %s)",
+			c.Tracing.Exporter, tracing.ExporterStdout, tracing.ExporterOTLP, tracing.ExporterNone))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		check("tracing.sample_ratio", fmt.Errorf("must be between 0 and 1, got %g", c.Tracing.SampleRatio))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// Logger builds the logger described by c.Log.
+func (c *Config) Logger() (*zap.Logger, error) {
+	level, err := zapcore.ParseLevel(c.Log.Level)
+	if err != nil {
+		return nil, err
+	}
+	zc := zap.NewProductionConfig()
+	zc.Level = zap.NewAtomicLevelAt(level)
+	if c.Log.Format == LogFormatConsole {
+		zc.Encoding = LogFormatConsole
+		zc.EncoderConfig = zap.NewDevelopmentEncoderConfig()
+	}
+	return zc.Build()
+}
+
+// Sink builds the sink that receives uploads, as configured by c.Uploads.
+func (c *Config) Sink() (arrow.ArrowSink, error) {
+	var schema *arrowgo.Schema
+	if c.Uploads.Schema != "" {
+		spec, err := arrow.LoadGeneratorSpec(c.Uploads.Schema)
+		if err != nil {
+			return nil, err
+		}
+		schema = spec.Schema()
+	}
+	switch c.Uploads.Sink {
+	case SinkDiscard:
+		return arrow.NewDiscardSink(schema), nil
+	case SinkMemory:
+		if schema == nil {
+			return nil, fmt.Errorf("sink %s requires an upload schema", SinkMemory)
+		}
+		return arrow.NewMemorySink(schema), nil
+	default:
+		return nil, fmt.Errorf("unknown sink %q", c.Uploads.Sink)
+	}
+}
+
+// Catalog opens every configured source and registers it, followed by the
+// demo dataset and, if sink can be read, the UploadsDataset dataset.
+func (c *Config) Catalog(sink arrow.ArrowSink) (*arrow.Catalog, error) {
+	catalog := arrow.NewCatalog()
+	for _, src := range c.Datasets.Sources {
+		ds, err := src.dataset()
+		if err != nil {
+			return nil, fmt.Errorf("open source %s: %w", src.Path, err)
+		}
+		if err := catalog.Register(ds); err != nil {
+			return nil, err
+		}
+	}
+	if c.Datasets.DemoRows > 0 {
+		spec := arrow.DefaultGeneratorSpec()
+		if c.Datasets.DemoSchema != "" {
+			var err error
+			if spec, err = arrow.LoadGeneratorSpec(c.Datasets.DemoSchema); err != nil {
+				return nil, err
+			}
+		}
+		service, err := arrow.NewDemoArrowServiceFromSpec(spec, c.Datasets.DemoRows)
+		if err != nil {
+			return nil, err
+		}
+		if err := catalog.Register(arrow.Dataset{
+			Name:        "demo",
+			Description: "Synthetic random data",
+			Service:     service,
+			Metadata:    map[string]string{"generator": "random"},
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if service, ok := sink.(arrow.ArrowService); ok {
+		if err := catalog.Register(arrow.Dataset{
+			Name:        UploadsDataset,
+			Description: "Batches uploaded by clients",
+			Service:     service,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return catalog, nil
+}
+
+func (s Source) dataset() (arrow.Dataset, error) {
+	format, err := arrow.DetectFormat(s.Path)
+	if err != nil {
+		return arrow.Dataset{}, err
+	}
+	var schema *arrowgo.Schema
+	if s.Schema != "" {
+		spec, err := arrow.LoadGeneratorSpec(s.Schema)
+		if err != nil {
+			return arrow.Dataset{}, err
+		}
+		schema = spec.Schema()
+	}
+	service, err := arrow.NewFileService(s.Path, schema)
+	if err != nil {
+		return arrow.Dataset{}, err
+	}
+	return arrow.Dataset{
+		Name:        s.DatasetName(),
+		Description: fmt.Sprintf("Data from %s", s.Path),
+		Service:     service,
+		Metadata:    map[string]string{"format": string(format), "source": s.Path},
+	}, nil
+}
+
+// ServerOptions returns the grpcserver options for every setting except the
+// address, loading the authentication config if there is one. Uploads go to
+// sink, which should come from Sink.
+func (c *Config) ServerOptions(sink arrow.ArrowSink) ([]grpcserver.Option, error) {
+	codec, err := arrow.ParseCodec(c.Compression)
+	if err != nil {
+		return nil, err
+	}
+	opts := []grpcserver.Option{
+		grpcserver.WithSink(sink),
+		grpcserver.WithBatchSize(c.BatchSize),
+		grpcserver.WithCompression(codec),
+		grpcserver.WithHealthCheckInterval(c.HealthInterval),
+		grpcserver.WithMaxMessageSize(c.Limits.MaxRecvMessageSize, c.Limits.MaxSendMessageSize),
+		grpcserver.WithTLS(grpcserver.TLSConfig{
+			CertFile:          c.TLS.CertFile,
+			KeyFile:           c.TLS.KeyFile,
+			ClientCAFile:      c.TLS.ClientCAFile,
+			RequireClientCert: c.TLS.RequireClientCert,
+		}),
+	}
+	if c.Keepalive != (Keepalive{}) {
+		opts = append(opts, grpcserver.WithKeepalive(grpcserver.KeepaliveConfig(c.Keepalive)))
+	}
+	if c.AuthConfig != "" {
+		cfg, err := auth.LoadConfig(c.AuthConfig)
+		if err != nil {
+			return nil, err
+		}
+		authn, policy, err := cfg.Build()
+		if err != nil {
+			return nil, fmt.Errorf("auth config %s: %w", c.AuthConfig, err)
+		}
+		opts = append(opts, grpcserver.WithAuth(authn, policy))
+	}
+	return opts, nil
+}
+
+// TracingConfig returns the tracing settings.
+func (c *Config) TracingConfig() tracing.Config {
+	return tracing.Config{
+		Exporter:    c.Tracing.Exporter,
+		Endpoint:    c.Tracing.Endpoint,
+		Insecure:    c.Tracing.Insecure,
+		SampleRatio: c.Tracing.SampleRatio,
+	}
+}
@@ -0,0 +1,383 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TFMV/ArrowLink/arrow"
+	"github.com/spf13/pflag"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func parseFlags(t *testing.T, args ...string) *pflag.FlagSet {
+	t.Helper()
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	RegisterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return fs
+}
+
+func TestPrecedence(t *testing.T) {
+	path := writeFile(t, "arrowlink.yaml", `
+address: ":1001"
+batch_size: 100
+compression: lz4
+health_interval: 1m
+log: {level: warn, format: console}
+keepalive: {time: 1m}
+datasets: {demo_rows: 10}
+`)
+	t.Setenv("ARROWLINK_BATCH_SIZE", "200")
+	t.Setenv("ARROWLINK_COMPRESSION", "zstd")
+	t.Setenv("ARROWLINK_LOG_LEVEL", "error")
+	t.Setenv("ARROWLINK_KEEPALIVE_TIME", "2m")
+
+	c, err := LoadFlags(parseFlags(t, "--config", path, "--compression", "none", "--keepalive-time", "3m"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := Default()
+	tests := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"default only", c.Tracing.Exporter, d.Tracing.Exporter},
+		{"file over default", c.Address, ":1001"},
+		{"file over default", c.HealthInterval, time.Minute},
+		{"file over default", c.Log.Format, LogFormatConsole},
+		{"file over default", c.Datasets.DemoRows, 10},
+		{"env over file", c.BatchSize, 200},
+		{"env over file", c.Log.Level, "error"},
+		{"flag over env", c.Compression, "none"},
+		{"flag over env", c.Keepalive.Time, 3 * time.Minute},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestUnsetFlagsKeepLowerSources(t *testing.T) {
+	t.Setenv("ARROWLINK_ADDRESS", ":2002")
+	t.Setenv("ARROWLINK_DATASETS_DEMO_ROWS", "5")
+	// The flag defaults equal Default(), but only flags set explicitly apply
+	c, err := LoadFlags(parseFlags(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Address != ":2002" || c.Datasets.DemoRows != 5 {
+		t.Errorf("address %q, demo rows %d; want the environment's", c.Address, c.Datasets.DemoRows)
+	}
+}
+
+func TestConfigFromEnvironment(t *testing.T) {
+	path := writeFile(t, "arrowlink.toml", `address = ":3003"`)
+	t.Setenv("ARROWLINK_CONFIG", path)
+	c, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Address != ":3003" {
+		t.Errorf("address = %q, want the file named by ARROWLINK_CONFIG", c.Address)
+	}
+
+	// --config wins over ARROWLINK_CONFIG
+	other := writeFile(t, "other.yaml", `address: ":4004"`)
+	c, err = LoadFlags(parseFlags(t, "--config", other))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Address != ":4004" {
+		t.Errorf("address = %q, want the file named by --config", c.Address)
+	}
+}
+
+func TestFlags(t *testing.T) {
+	t.Setenv("ARROWLINK_DATASETS_SOURCES", "a=one.csv, two.parquet")
+	c, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Source{{Name: "a", Path: "one.csv"}, {Path: "two.parquet"}}
+	if !reflect.DeepEqual(c.Datasets.Sources, want) {
+		t.Errorf("sources from the environment = %v, want %v", c.Datasets.Sources, want)
+	}
+
+	c.ApplyFlags(parseFlags(t, "--address", "host:1", "--port", "9000", "--source", "three.csv", "--source", "b=four.csv", "--trace-sample-ratio", "0.5"))
+	if c.Address != ":9000" {
+		t.Errorf("address = %q, want --port to win", c.Address)
+	}
+	want = []Source{{Path: "three.csv"}, {Name: "b", Path: "four.csv"}}
+	if !reflect.DeepEqual(c.Datasets.Sources, want) {
+		t.Errorf("sources = %v, want --source to replace them: %v", c.Datasets.Sources, want)
+	}
+	if c.Tracing.SampleRatio != 0.5 {
+		t.Errorf("sample ratio = %g", c.Tracing.SampleRatio)
+	}
+}
+
+func TestLoadFileFormats(t *testing.T) {
+	want := Default()
+	want.Address = ":5005"
+	want.Keepalive.MinTime = 30 * time.Second
+	want.Datasets.Sources = []Source{{Name: "trips", Path: "trips.parquet"}}
+
+	for name, content := range map[string]string{
+		"c.yaml": "address: \":5005\"\nkeepalive: {min_time: 30s}\ndatasets: {sources: [{name: trips, path: trips.parquet}]}\n",
+		"c.yml":  "address: \":5005\"\nkeepalive:\n  min_time: 30s\ndatasets:\n  sources:\n    - name: trips\n      path: trips.parquet\n",
+		"c.json": `{"address": ":5005", "keepalive": {"min_time": "30s"}, "datasets": {"sources": [{"name": "trips", "path": "trips.parquet"}]}}`,
+		"c.toml": "address = \":5005\"\n[keepalive]\nmin_time = \"30s\"\n[[datasets.sources]]\nname = \"trips\"\npath = \"trips.parquet\"\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := Default()
+			if err := c.LoadFile(writeFile(t, name, content)); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(c, want) {
+				t.Errorf("got %+v, want %+v", c, want)
+			}
+		})
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	for name, content := range map[string]string{
+		"unknown.yaml":        "adress: \":1\"\n",
+		"unknown-nested.yaml": "log: {lvl: info}\n",
+		"unknown.toml":        "adress = \":1\"\n",
+		"unknown-nested.toml": "[log]\nlvl = \"info\"\n",
+		"mistyped.yaml":       "batch_size: many\n",
+		"mistyped.toml":       "batch_size = \"many\"\n",
+		"config.ini":          "address=:1\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := Default().LoadFile(writeFile(t, name, content)); err == nil {
+				t.Error("LoadFile succeeded")
+			}
+		})
+	}
+	if err := Default().LoadFile(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("LoadFile of a missing file succeeded")
+	}
+}
+
+func TestEmptyFileKeepsDefaults(t *testing.T) {
+	c := Default()
+	if err := c.LoadFile(writeFile(t, "empty.yaml", "")); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c, Default()) {
+		t.Errorf("an empty file changed the configuration: %+v", c)
+	}
+}
+
+func TestLoadEnvErrors(t *testing.T) {
+	env := map[string]string{
+		"ARROWLINK_BATCH_SIZE":              "many",
+		"ARROWLINK_HEALTH_INTERVAL":         "soon",
+		"ARROWLINK_OTLP_INSECURE":           "maybe",
+		"ARROWLINK_TLS_REQUIRE_CLIENT_CERT": "maybe",
+	}
+	err := Default().loadEnv(func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	})
+	if err == nil {
+		t.Fatal("loadEnv succeeded")
+	}
+	// ARROWLINK_OTLP_INSECURE is not a setting; tracing.insecure is
+	// ARROWLINK_TRACING_INSECURE.
+	for _, name := range []string{"ARROWLINK_BATCH_SIZE", "ARROWLINK_HEALTH_INTERVAL", "ARROWLINK_TLS_REQUIRE_CLIENT_CERT"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error does not mention %s:\n%v", name, err)
+		}
+	}
+	if strings.Contains(err.Error(), "ARROWLINK_OTLP_INSECURE") {
+		t.Errorf("error mentions a variable that is not a setting:\n%v", err)
+	}
+}
+
+func TestValidateDefault(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Errorf("the default configuration is invalid: %v", err)
+	}
+}
+
+func TestValidateReportsEveryError(t *testing.T) {
+	c := Default()
+	c.Address = "no-port"
+	c.Compression = "brotli"
+	c.BatchSize = 0
+	c.HealthInterval = -time.Second
+	c.MetricsAddress = "9090"
+	c.AuthConfig = filepath.Join(t.TempDir(), "missing.json")
+	c.TLS.CertFile = "server.crt"
+	c.TLS.RequireClientCert = true
+	c.Log = Log{Level: "loud", Format: "xml"}
+	c.Limits.MaxRecvMessageSize = -1
+	c.Limits.MaxSendMessageSize = -1
+	c.Keepalive.Time = -time.Second
+	c.Keepalive.Timeout = -time.Second
+	c.Keepalive.MinTime = -time.Second
+	c.Uploads = Uploads{Schema: filepath.Join(t.TempDir(), "missing.yaml"), Sink: "s3"}
+	c.Datasets.DemoRows = -1
+	c.Datasets.Sources = []Source{{Name: "x"}, {Path: "data.txt"}, {Path: "a/trips.csv"}, {Path: "b/trips.parquet"}, {Path: "c.csv", Schema: "missing.yaml"}}
+	c.Tracing = Tracing{Exporter: "jaeger", SampleRatio: 2}
+
+	err := c.Validate()
+	if err == nil {
+		t.Fatal("Validate succeeded")
+	}
+	for _, key := range []string{
+		"address:",
+		"compression:",
+		"batch_size:",
+		"health_interval:",
+		"metrics_address:",
+		"auth_config:",
+		"tls: cert_file and key_file must be set together",
+		"tls.require_client_cert:",
+		"tls.cert_file:",
+		"log.level:",
+		"log.format:",
+		"limits.max_recv_message_size:",
+		"limits.max_send_message_size:",
+		"keepalive.time:",
+		"keepalive.timeout:",
+		"keepalive.min_time:",
+		"uploads.schema:",
+		"uploads.sink: unknown sink \"s3\"",
+		"datasets.demo_rows:",
+		"datasets.sources[0]: path is required",
+		"datasets.sources[1]:",
+		"datasets.sources[3]: duplicate dataset name \"trips\"",
+		"datasets.sources[4].schema:",
+		"tracing.exporter:",
+		"tracing.sample_ratio:",
+	} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("error does not report %q:\n%v", key, err)
+		}
+	}
+}
+
+func TestValidateRequiresDatasets(t *testing.T) {
+	c := Default()
+	c.Datasets.DemoRows = 0
+	err := c.Validate()
+	if err == nil || !strings.Contains(err.Error(), "no datasets configured") {
+		t.Errorf("Validate() = %v, want a missing datasets error", err)
+	}
+}
+
+func TestUploads(t *testing.T) {
+	schema := writeFile(t, "uploads.yaml", "columns:\n  - {name: id, type: int64}\n  - {name: label, type: string}\n")
+	c := Default()
+	c.ApplyFlags(parseFlags(t, "--upload-schema", schema, "--upload-sink", "memory"))
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	sink, err := c.Sink()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sink.(*arrow.MemorySink); !ok {
+		t.Errorf("sink is a %T, want a memory sink", sink)
+	}
+	if got := sink.Schema().String(); !strings.Contains(got, "id: type=int64") || !strings.Contains(got, "label: type=utf8") {
+		t.Errorf("sink schema = %s", got)
+	}
+	catalog, err := c.Catalog(sink)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := catalog.Lookup(UploadsDataset); !ok {
+		t.Errorf("the memory sink is not published as %q", UploadsDataset)
+	}
+
+	// The default sink accepts anything and is not published
+	sink, err = Default().Sink()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sink.Schema() != nil {
+		t.Errorf("default sink schema = %s, want none", sink.Schema())
+	}
+	catalog, err = Default().Catalog(sink)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := catalog.Lookup(UploadsDataset); ok {
+		t.Errorf("the discard sink is published as %q", UploadsDataset)
+	}
+}
+
+func TestValidateUploads(t *testing.T) {
+	c := Default()
+	c.Uploads.Sink = SinkMemory
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "uploads.sink: memory requires uploads.schema") {
+		t.Errorf("Validate() = %v, want a missing schema error", err)
+	}
+
+	c = Default()
+	c.Uploads = Uploads{Schema: writeFile(t, "uploads.json", `{"columns": [{"name": "id", "type": "int64"}]}`), Sink: SinkMemory}
+	c.Datasets.Sources = []Source{{Path: "data/uploads.csv"}}
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), `duplicate dataset name "uploads"`) {
+		t.Errorf("Validate() = %v, want a duplicate name error", err)
+	}
+}
+
+func TestSourceSchema(t *testing.T) {
+	data := writeFile(t, "prices.csv", "id,price\n1,5\n2,6\n")
+	c := Default()
+	c.Datasets.Sources = []Source{{Path: data, Schema: writeFile(t, "prices.yaml", "columns:\n  - {name: id, type: int64}\n  - {name: price, type: float64}\n")}}
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	catalog, err := c.Catalog(arrow.NewDiscardSink(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds, ok := catalog.Lookup("prices")
+	if !ok {
+		t.Fatal("the source is not published")
+	}
+	schema, err := arrow.ServiceSchema(context.Background(), ds.Service)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Inferred from the data, price would be an int64
+	if got := schema.Field(1).Type.String(); got != "float64" {
+		t.Errorf("price is %s, want the configured float64", got)
+	}
+}
+
+func TestSourceDatasetName(t *testing.T) {
+	for spec, want := range map[string]string{
+		"data/trips.parquet":   "trips",
+		"data/trips":           "trips",
+		"rides=data/trips.csv": "rides",
+		"archive.tar.csv":      "archive.tar",
+	} {
+		if got := ParseSource(spec).DatasetName(); got != want {
+			t.Errorf("ParseSource(%q).DatasetName() = %q, want %q", spec, got, want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvPrefix starts the name of every environment variable read by LoadEnv.
+const EnvPrefix = "ARROWLINK_"
+
+// LoadEnv overrides c with ARROWLINK_* environment variables. Each setting is
+// named after its path in the config file, upper-cased and joined by
+// underscores: log.level is ARROWLINK_LOG_LEVEL and keepalive.min_time is
+// ARROWLINK_KEEPALIVE_MIN_TIME. ARROWLINK_DATASETS_SOURCES holds a
+// comma-separated list of [name=]path sources.
+func (c *Config) LoadEnv() error {
+	return c.loadEnv(os.LookupEnv)
+}
+
+func (c *Config) loadEnv(lookup func(string) (string, bool)) error {
+	var errs []error
+	for _, f := range envFields(reflect.ValueOf(c).Elem(), strings.TrimSuffix(EnvPrefix, "_")) {
+		value, ok := lookup(f.name)
+		if !ok {
+			continue
+		}
+		if err := setEnv(f.value, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s=%q: %w", f.name, value, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid environment:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+type envField struct {
+	name  string
+	value reflect.Value
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	sourcesType  = reflect.TypeOf([]Source(nil))
+)
+
+// envFields lists the settings in the struct v with their variable names.
+func envFields(v reflect.Value, prefix string) []envField {
+	var fields []envField
+	for i := 0; i < v.NumField(); i++ {
+		key, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("yaml"), ",")
+		name := prefix + "_" + strings.ToUpper(key)
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			fields = append(fields, envFields(field, name)...)
+			continue
+		}
+		fields = append(fields, envField{name: name, value: field})
+	}
+	return fields
+}
+
+func setEnv(v reflect.Value, s string) error {
+	switch v.Type() {
+	case durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	case sourcesType:
+		var sources []Source
+		for _, spec := range strings.Split(s, ",") {
+			if spec = strings.TrimSpace(spec); spec != "" {
+				sources = append(sources, ParseSource(spec))
+			}
+		}
+		v.Set(reflect.ValueOf(sources))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+	return nil
+}
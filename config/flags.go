@@ -0,0 +1,122 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// RegisterFlags defines the server's command line flags on fs, with the
+// defaults of Default.
+func RegisterFlags(fs *pflag.FlagSet) {
+	d := Default()
+	fs.String("config", "", "YAML or TOML config file (default: $"+EnvPrefix+"CONFIG)")
+	fs.String("address", d.Address, "Address to listen on")
+	fs.StringP("port", "p", "", "Port to listen on, on every interface; shorthand for --address :PORT")
+	fs.IntP("rows", "r", d.Datasets.DemoRows, "Number of rows in the demo dataset (0: no demo dataset)")
+	fs.String("schema", "", "JSON or YAML spec of the demo dataset's columns and their distributions")
+	fs.IntP("batch-size", "b", d.BatchSize, "Maximum rows per streamed record batch")
+	fs.StringArray("source", nil, "Serve a CSV or Parquet file or directory as a dataset, as [name=]path (repeatable; replaces configured sources)")
+	fs.String("compression", d.Compression, "Default IPC body compression: none, lz4 or zstd")
+	fs.String("log-level", d.Log.Level, "Log level: debug, info, warn or error")
+	fs.String("log-format", d.Log.Format, "Log format: json or console")
+	fs.String("tls-cert", "", "PEM server certificate; enables TLS")
+	fs.String("tls-key", "", "PEM server private key")
+	fs.String("tls-client-ca", "", "PEM bundle of CAs trusted to sign client certificates")
+	fs.Bool("tls-require-client-cert", false, "Require clients to present a certificate (mutual TLS)")
+	fs.Int("max-recv-msg-size", 0, "Largest message the server accepts, in bytes (0: gRPC default of 4 MiB)")
+	fs.Int("max-send-msg-size", 0, "Largest message the server sends, in bytes (0: unlimited)")
+	fs.Duration("keepalive-time", 0, "Ping clients after this long without activity (0: gRPC default)")
+	fs.Duration("keepalive-timeout", 0, "Close connections whose ping is not answered within this long (0: gRPC default)")
+	fs.Duration("keepalive-min-time", 0, "Disconnect clients that ping more often than this (0: gRPC default)")
+	fs.Bool("keepalive-permit-without-stream", false, "Allow client pings on connections without active calls")
+	fs.Duration("health-interval", d.HealthInterval, "How often to check that every dataset can produce its schema (0: only at startup)")
+	fs.String("metrics-addr", "", "Address to serve Prometheus metrics on at /metrics, e.g. :9090 (default: disabled)")
+	fs.String("trace-exporter", d.Tracing.Exporter, "Export OpenTelemetry traces: none, stdout or otlp")
+	fs.String("otlp-endpoint", d.Tracing.Endpoint, "OTLP/gRPC collector address for --trace-exporter otlp")
+	fs.Bool("otlp-insecure", d.Tracing.Insecure, "Connect to the OTLP collector without TLS")
+	fs.Float64("trace-sample-ratio", 0, "Fraction of new traces to record (0: all)")
+	fs.String("auth-config", "", "JSON file of API keys, JWT settings and access rules; enables authentication")
+	fs.String("upload-schema", "", "JSON or YAML file of the columns uploads must match, in the format of --schema (default: any schema)")
+	fs.String("upload-sink", d.Uploads.Sink, "What to do with uploads: discard, or memory to serve them as the uploads dataset (requires --upload-schema)")
+}
+
+// LoadFlags loads the config file named by --config or $ARROWLINK_CONFIG,
+// then the environment, and finally applies the flags set on fs, which
+// must have been defined by RegisterFlags. The result is validated.
+func LoadFlags(fs *pflag.FlagSet) (*Config, error) {
+	path, _ := fs.GetString("config")
+	c, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	c.ApplyFlags(fs)
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ApplyFlags overrides c with the flags explicitly set on fs.
+func (c *Config) ApplyFlags(fs *pflag.FlagSet) {
+	str := func(name string, dst *string) {
+		if fs.Changed(name) {
+			*dst, _ = fs.GetString(name)
+		}
+	}
+	integer := func(name string, dst *int) {
+		if fs.Changed(name) {
+			*dst, _ = fs.GetInt(name)
+		}
+	}
+	boolean := func(name string, dst *bool) {
+		if fs.Changed(name) {
+			*dst, _ = fs.GetBool(name)
+		}
+	}
+	duration := func(name string, dst *time.Duration) {
+		if fs.Changed(name) {
+			*dst, _ = fs.GetDuration(name)
+		}
+	}
+
+	str("address", &c.Address)
+	if fs.Changed("port") {
+		port, _ := fs.GetString("port")
+		c.Address = ":" + port
+	}
+	integer("rows", &c.Datasets.DemoRows)
+	str("schema", &c.Datasets.DemoSchema)
+	integer("batch-size", &c.BatchSize)
+	if fs.Changed("source") {
+		specs, _ := fs.GetStringArray("source")
+		c.Datasets.Sources = nil
+		for _, spec := range specs {
+			c.Datasets.Sources = append(c.Datasets.Sources, ParseSource(spec))
+		}
+	}
+	str("compression", &c.Compression)
+	str("log-level", &c.Log.Level)
+	str("log-format", &c.Log.Format)
+	str("tls-cert", &c.TLS.CertFile)
+	str("tls-key", &c.TLS.KeyFile)
+	str("tls-client-ca", &c.TLS.ClientCAFile)
+	boolean("tls-require-client-cert", &c.TLS.RequireClientCert)
+	integer("max-recv-msg-size", &c.Limits.MaxRecvMessageSize)
+	integer("max-send-msg-size", &c.Limits.MaxSendMessageSize)
+	duration("keepalive-time", &c.Keepalive.Time)
+	duration("keepalive-timeout", &c.Keepalive.Timeout)
+	duration("keepalive-min-time", &c.Keepalive.MinTime)
+	boolean("keepalive-permit-without-stream", &c.Keepalive.PermitWithoutStream)
+	duration("health-interval", &c.HealthInterval)
+	str("metrics-addr", &c.MetricsAddress)
+	str("trace-exporter", &c.Tracing.Exporter)
+	str("otlp-endpoint", &c.Tracing.Endpoint)
+	boolean("otlp-insecure", &c.Tracing.Insecure)
+	if fs.Changed("trace-sample-ratio") {
+		c.Tracing.SampleRatio, _ = fs.GetFloat64("trace-sample-ratio")
+	}
+	str("auth-config", &c.AuthConfig)
+	str("upload-schema", &c.Uploads.Schema)
+	str("upload-sink", &c.Uploads.Sink)
+}
@@ -0,0 +1,64 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/TFMV/ArrowLink/auth"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// WithAuth requires every call except health checks to authenticate with
+// authn and limits each caller to the RPCs and datasets policy grants. A nil
+// policy lets every authenticated caller use everything.
+func WithAuth(authn auth.Authenticator, policy *auth.Policy) Option {
+	return func(s *Server) {
+		s.authn = authn
+		s.policy = policy
+	}
+}
+
+// publicMethod reports whether fullMethod may be called without credentials.
+// Health checks are public so that orchestrators and load balancers can
+// probe the server.
+func publicMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+healthpb.Health_ServiceDesc.ServiceName+"/")
+}
+
+// authUnaryInterceptor authenticates unary calls to every method but the
+// public ones.
+func (s *Server) authUnaryInterceptor() grpc.UnaryServerInterceptor {
+	check := auth.UnaryServerInterceptor(s.authn, s.policy)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if publicMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		return check(ctx, req, info, handler)
+	}
+}
+
+// authStreamInterceptor is the streaming counterpart of authUnaryInterceptor.
+func (s *Server) authStreamInterceptor() grpc.StreamServerInterceptor {
+	check := auth.StreamServerInterceptor(s.authn, s.policy)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if publicMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		return check(srv, ss, info, handler)
+	}
+}
+
+// allowDataset reports whether the caller in ctx may use the current RPC on
+// dataset. It allows everything when authentication is off.
+func (s *Server) allowDataset(ctx context.Context, dataset string) bool {
+	if s.authn == nil || s.policy == nil {
+		return true
+	}
+	id, ok := auth.FromContext(ctx)
+	if !ok {
+		return false
+	}
+	method, _ := grpc.Method(ctx)
+	return s.policy.AllowDataset(id, auth.MethodName(method), dataset)
+}
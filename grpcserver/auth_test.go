@@ -0,0 +1,131 @@
+package grpcserver
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/TFMV/ArrowLink/arrow"
+	"github.com/TFMV/ArrowLink/auth"
+	pb "github.com/TFMV/ArrowLink/proto/dataexchange"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+)
+
+// startAuthServer serves a "demo" dataset that analysts may read and a
+// "payroll" dataset that nobody may.
+func startAuthServer(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+	keys, err := auth.NewAPIKeyAuthenticator([]auth.APIKey{
+		{Key: "analyst-key", Subject: "alice", Groups: []string{"analysts"}},
+		{Key: "etl-key", Subject: "etl"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := &auth.Policy{Rules: []auth.Rule{
+		{Subjects: []string{"group:analysts"}, Methods: []string{"GetArrowData", "ListDatasets"}, Datasets: []string{"demo"}},
+		{Subjects: []string{"etl"}, Methods: []string{"SendArrowData"}},
+	}}
+	cat := testCatalog(t, map[string]arrow.ArrowService{
+		"demo":    arrow.NewDemoArrowService(10),
+		"payroll": arrow.NewDemoArrowService(10),
+	})
+	return startTestServer(t, cat, WithAuth(keys, policy))
+}
+
+func withAPIKey(key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), auth.APIKeyHeader, key)
+}
+
+// readAll drains a GetArrowData stream and returns the status it ended with.
+func readAll(stream pb.ArrowDataService_GetArrowDataClient) error {
+	for {
+		if _, err := stream.Recv(); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func TestAuthGetArrowData(t *testing.T) {
+	client := pb.NewArrowDataServiceClient(startAuthServer(t))
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		dataset string
+		want    codes.Code
+	}{
+		{"granted dataset", withAPIKey("analyst-key"), "demo", codes.OK},
+		{"other dataset", withAPIKey("analyst-key"), "payroll", codes.PermissionDenied},
+		{"method not granted", withAPIKey("etl-key"), "demo", codes.PermissionDenied},
+		{"bad key", withAPIKey("guess"), "demo", codes.Unauthenticated},
+		{"no credentials", context.Background(), "demo", codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.GetArrowData(tt.ctx, &pb.DataRequest{Dataset: tt.dataset})
+			if err == nil {
+				err = readAll(stream)
+			}
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("code = %s, want %s (%v)", got, tt.want, err)
+			}
+		})
+	}
+}
+
+func TestAuthListDatasetsFiltersDatasets(t *testing.T) {
+	client := pb.NewArrowDataServiceClient(startAuthServer(t))
+	list, err := client.ListDatasets(withAPIKey("analyst-key"), &pb.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Datasets) != 1 || list.Datasets[0].Name != "demo" {
+		t.Errorf("datasets = %v, want only demo", list.Datasets)
+	}
+}
+
+func TestAuthHealthIsPublic(t *testing.T) {
+	conn := startAuthServer(t)
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("unauthenticated health check failed: %v", err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("status = %s, want SERVING", resp.Status)
+	}
+}
+
+func TestAuthReflectionIsDenied(t *testing.T) {
+	conn := startAuthServer(t)
+	for name, ctx := range map[string]context.Context{
+		"no credentials":     context.Background(),
+		"method not granted": withAPIKey("analyst-key"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+			if err == nil {
+				err = stream.Send(&reflectionpb.ServerReflectionRequest{
+					MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+				})
+			}
+			if err == nil || err == io.EOF {
+				_, err = stream.Recv()
+			}
+			want := codes.Unauthenticated
+			if name == "method not granted" {
+				want = codes.PermissionDenied
+			}
+			if got := status.Code(err); got != want {
+				t.Errorf("code = %s, want %s (%v)", got, want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package grpcserver
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/TFMV/ArrowLink/arrow"
+	pb "github.com/TFMV/ArrowLink/proto/dataexchange"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata keys used to negotiate IPC body compression. Clients may set them
+// on the request instead of using the DataRequest fields (Flight clients have
+// no other way), and the server sets them on the response header to report
+// the codec it used.
+const (
+	CompressionHeader      = "arrowlink-compression"
+	CompressionLevelHeader = "arrowlink-compression-level"
+)
+
+// WithCompression sets the codec used when a request does not choose one.
+func WithCompression(codec arrow.Codec) Option {
+	return func(s *Server) {
+		s.compression = codec
+	}
+}
+
+// negotiateCompression picks the codec for a request: the request field wins,
+// then the request metadata, then the server default.
+func (s *Server) negotiateCompression(ctx context.Context, codec pb.Compression, level int32) (arrow.Codec, error) {
+	var chosen arrow.Codec
+	switch codec {
+	case pb.Compression_COMPRESSION_NONE:
+		chosen = arrow.CodecNone
+	case pb.Compression_COMPRESSION_LZ4_FRAME:
+		chosen = arrow.CodecLZ4Frame
+	case pb.Compression_COMPRESSION_ZSTD:
+		chosen = arrow.CodecZstd
+	default:
+		chosen = s.compression
+		md, _ := metadata.FromIncomingContext(ctx)
+		if v := md.Get(CompressionHeader); len(v) > 0 {
+			c, err := arrow.ParseCodec(v[0])
+			if err != nil {
+				return "", status.Error(codes.InvalidArgument, err.Error())
+			}
+			chosen = c
+		}
+		if v := md.Get(CompressionLevelHeader); len(v) > 0 && level == 0 {
+			l, err := strconv.Atoi(v[0])
+			if err != nil {
+				return "", status.Errorf(codes.InvalidArgument, "invalid %s %q", CompressionLevelHeader, v[0])
+			}
+			level = int32(l)
+		}
+	}
+
+	if err := chosen.ValidateLevel(int(level)); err != nil {
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+	return chosen, nil
+}
+
+// reportCompression tells the client which codec the stream uses. The level
+// is always the codec default, since no other is accepted; see
+// arrow.Codec.ValidateLevel.
+func reportCompression(stream grpc.ServerStream, codec arrow.Codec) error {
+	return stream.SendHeader(metadata.Pairs(
+		CompressionHeader, string(codec),
+		CompressionLevelHeader, "default",
+	))
+}
@@ -0,0 +1,75 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/TFMV/ArrowLink/arrow"
+	pb "github.com/TFMV/ArrowLink/proto/dataexchange"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListDatasets describes every dataset registered in the catalog that the
+// caller may list.
+func (s *Server) ListDatasets(ctx context.Context, req *pb.Empty) (*pb.DatasetList, error) {
+	list := &pb.DatasetList{}
+	for _, ds := range s.catalog.List() {
+		if !s.allowDataset(ctx, ds.Name) {
+			continue
+		}
+		info, err := s.datasetInfo(ctx, ds)
+		if err != nil {
+			return nil, err
+		}
+		list.Datasets = append(list.Datasets, info)
+	}
+	return list, nil
+}
+
+// DescribeDataset returns the schema, row count estimate and metadata of a
+// single dataset.
+func (s *Server) DescribeDataset(ctx context.Context, req *pb.DatasetRequest) (*pb.DatasetInfo, error) {
+	ds, err := s.dataset(ctx, req.GetDataset())
+	if err != nil {
+		return nil, err
+	}
+	return s.datasetInfo(ctx, ds)
+}
+
+// dataset resolves a dataset name, returning NotFound for unknown names and
+// PermissionDenied for datasets the caller may not use with this RPC.
+func (s *Server) dataset(ctx context.Context, name string) (ds arrow.Dataset, err error) {
+	ctx, span := tracer.Start(ctx, "ResolveDataset", trace.WithAttributes(attribute.String("arrowlink.dataset", name)))
+	defer func() { endSpan(span, err) }()
+
+	ds, ok := s.catalog.Lookup(name)
+	if !ok {
+		if name == "" {
+			return arrow.Dataset{}, status.Error(codes.NotFound, "no datasets are registered")
+		}
+		return arrow.Dataset{}, status.Errorf(codes.NotFound, "unknown dataset %q", name)
+	}
+	if !s.allowDataset(ctx, ds.Name) {
+		return arrow.Dataset{}, status.Errorf(codes.PermissionDenied, "access to dataset %q is denied", ds.Name)
+	}
+	return ds, nil
+}
+
+func (s *Server) datasetInfo(ctx context.Context, ds arrow.Dataset) (*pb.DatasetInfo, error) {
+	schema, err := arrow.ServiceSchema(ctx, ds.Service)
+	if err != nil {
+		s.logger.Error("failed to get dataset schema", zap.String("dataset", ds.Name), zap.Error(err))
+		return nil, toStatus(err)
+	}
+	return &pb.DatasetInfo{
+		Name:          ds.Name,
+		Description:   ds.Description,
+		Schema:        flight.SerializeSchema(schema, s.mem),
+		EstimatedRows: ds.EstimatedRows(),
+		Metadata:      ds.Metadata,
+	}, nil
+}
@@ -0,0 +1,213 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/TFMV/ArrowLink/arrow"
+	"github.com/TFMV/ArrowLink/metrics"
+	pb "github.com/TFMV/ArrowLink/proto/dataexchange"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// flightServer exposes a Server's catalog and sink through the Arrow Flight
+// protocol, so standard Flight clients such as pyarrow.flight can read and
+// write ArrowLink datasets. Each dataset is published under a single-element
+// path holding its name, and its tickets carry the same name.
+type flightServer struct {
+	flight.BaseFlightServer
+	srv *Server
+}
+
+// NewFlightServer creates a Flight service backed by the same catalog, sink
+// and batch size as s. Register it with flight.RegisterFlightServiceServer.
+func NewFlightServer(s *Server) flight.FlightServer {
+	return &flightServer{srv: s}
+}
+
+// ListFlights sends the FlightInfo of every dataset in the catalog that the
+// caller may list.
+func (f *flightServer) ListFlights(criteria *flight.Criteria, stream flight.FlightService_ListFlightsServer) error {
+	for _, ds := range f.srv.catalog.List() {
+		if !f.srv.allowDataset(stream.Context(), ds.Name) {
+			continue
+		}
+		info, err := f.flightInfo(stream.Context(), ds)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetFlightInfo describes the dataset named by the descriptor and returns a
+// single endpoint whose ticket can be passed to DoGet.
+func (f *flightServer) GetFlightInfo(ctx context.Context, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	ds, err := f.datasetFromDescriptor(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	return f.flightInfo(ctx, ds)
+}
+
+// GetSchema returns the serialized schema of the dataset named by the descriptor.
+func (f *flightServer) GetSchema(ctx context.Context, desc *flight.FlightDescriptor) (*flight.SchemaResult, error) {
+	ds, err := f.datasetFromDescriptor(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := arrow.ServiceSchema(ctx, ds.Service)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &flight.SchemaResult{Schema: flight.SerializeSchema(schema, f.srv.mem)}, nil
+}
+
+// DoGet streams the dataset named by the ticket as Flight record batches,
+// compressed with the codec requested in the arrowlink-compression metadata.
+func (f *flightServer) DoGet(ticket *flight.Ticket, stream flight.FlightService_DoGetServer) (err error) {
+	ctx := stream.Context()
+	ds, err := f.srv.dataset(ctx, string(ticket.GetTicket()))
+	if err != nil {
+		return err
+	}
+	codec, err := f.srv.negotiateCompression(ctx, pb.Compression_COMPRESSION_UNSPECIFIED, 0)
+	if err != nil {
+		return err
+	}
+
+	reader, err := ds.Service.GetData(ctx, arrow.ReadOptions{BatchSize: f.srv.batchSize, Allocator: f.srv.mem})
+	if err != nil {
+		f.srv.logger.Error("failed to get arrow data", zap.Error(err))
+		return toStatus(err)
+	}
+	defer reader.Release()
+
+	if err := reportCompression(stream, codec); err != nil {
+		return err
+	}
+	method, _ := grpc.Method(ctx)
+	streamed := f.srv.metrics.Stream(method, ds.Name, metrics.Sent)
+	defer streamed.Done()
+
+	metered := &meteredFlightStream{FlightService_DoGetServer: stream}
+	opts := append([]ipc.Option{ipc.WithSchema(reader.Schema()), ipc.WithAllocator(f.srv.mem)}, codec.IPCOptions()...)
+	writer := flight.NewRecordWriter(metered, opts...)
+	// A writer that has written nothing sends the schema when it closes, so
+	// an empty result can still fail here
+	defer func() {
+		if cerr := writer.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	for {
+		start := time.Now()
+		if !reader.Next() {
+			break
+		}
+		f.srv.metrics.ObserveGeneration(ds.Name, time.Since(start))
+
+		rec := reader.Record()
+		bytes, sending := metered.bytes, metered.sending
+		start = time.Now()
+		_, span := tracer.Start(ctx, "WriteBatch", trace.WithAttributes(
+			attribute.String("arrow.codec", string(codec)),
+			attribute.Int64("arrow.rows", rec.NumRows()),
+		))
+		err := writer.Write(rec)
+		endSpan(span, err)
+		if err != nil {
+			return err
+		}
+		// The writer sends as it serializes; only the encoding is timed. The
+		// messages go to the stream without being copied, so all of it counts
+		// as encoding, compression included.
+		encoding := time.Since(start) - (metered.sending - sending)
+		f.srv.observeSerialization(codec, arrow.SerializeTimings{Total: encoding, Encode: encoding})
+		streamed.Add(1, rec.NumRows(), metered.bytes-bytes)
+	}
+	if err := reader.Err(); err != nil {
+		if ctx.Err() == nil {
+			f.srv.logger.Error("failed to get arrow data", zap.Error(err))
+		}
+		return toStatus(err)
+	}
+	return nil
+}
+
+// DoPut writes the uploaded record batches to the server's sink. The upload is
+// rejected with InvalidArgument if its schema does not match the sink's.
+func (f *flightServer) DoPut(stream flight.FlightService_DoPutServer) error {
+	metered := &meteredFlightUpload{FlightService_DoPutServer: stream}
+	reader, err := flight.NewRecordReader(metered, ipc.WithAllocator(f.srv.mem))
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to read flight data: %v", err)
+	}
+	defer reader.Release()
+
+	if err := arrow.CheckSchema(f.srv.sink.Schema(), reader.Schema()); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	method, _ := grpc.Method(stream.Context())
+	received := f.srv.metrics.Stream(method, "", metrics.Received)
+	defer received.Done()
+
+	var rows, batches int64
+	bytes := 0
+	for reader.Next() {
+		rec := reader.Record()
+		if err := f.srv.sink.Write(rec); err != nil {
+			f.srv.logger.Error("failed to write arrow data", zap.Error(err))
+			return status.Errorf(codes.Internal, "batch %d: %v", batches, err)
+		}
+		rows += rec.NumRows()
+		batches++
+		received.Add(1, rec.NumRows(), metered.bytes-bytes)
+		bytes = metered.bytes
+	}
+	if err := reader.Err(); err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to read flight data: %v", err)
+	}
+
+	f.srv.logger.Info("received flight upload", zap.Int64("rows", rows), zap.Int64("batches", batches))
+	return nil
+}
+
+func (f *flightServer) flightInfo(ctx context.Context, ds arrow.Dataset) (*flight.FlightInfo, error) {
+	schema, err := arrow.ServiceSchema(ctx, ds.Service)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &flight.FlightInfo{
+		Schema:           flight.SerializeSchema(schema, f.srv.mem),
+		FlightDescriptor: &flight.FlightDescriptor{Type: flight.DescriptorPATH, Path: []string{ds.Name}},
+		Endpoint:         []*flight.FlightEndpoint{{Ticket: &flight.Ticket{Ticket: []byte(ds.Name)}}},
+		TotalRecords:     ds.EstimatedRows(),
+		TotalBytes:       -1,
+	}, nil
+}
+
+// datasetFromDescriptor resolves a single-element path or a command holding
+// the dataset name.
+func (f *flightServer) datasetFromDescriptor(ctx context.Context, desc *flight.FlightDescriptor) (arrow.Dataset, error) {
+	switch {
+	case desc.GetType() == flight.DescriptorPATH && len(desc.GetPath()) == 1:
+		return f.srv.dataset(ctx, desc.GetPath()[0])
+	case desc.GetType() == flight.DescriptorCMD:
+		return f.srv.dataset(ctx, string(desc.GetCmd()))
+	default:
+		return arrow.Dataset{}, status.Error(codes.InvalidArgument, "descriptor must be a single-element path or a command naming a dataset")
+	}
+}
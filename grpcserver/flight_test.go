@@ -0,0 +1,115 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TFMV/ArrowLink/arrow"
+	"github.com/TFMV/ArrowLink/metrics"
+	arrowgo "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// histogramCount returns the number of observations of the histogram whose
+// name ends with suffix, for the series labelled codec.
+func histogramCount(t *testing.T, m *metrics.Metrics, suffix, codec string) uint64 {
+	t.Helper()
+	families, err := m.Registry().Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range families {
+		if !strings.HasSuffix(f.GetName(), suffix) {
+			continue
+		}
+		for _, metric := range f.GetMetric() {
+			for _, l := range metric.GetLabel() {
+				if l.GetName() == "codec" && l.GetValue() == codec {
+					return metric.GetHistogram().GetSampleCount()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestFlightDoGetMetrics(t *testing.T) {
+	m := metrics.New()
+	cat := testCatalog(t, map[string]arrow.ArrowService{"demo": arrow.NewDemoArrowService(1000)})
+	conn := startTestServer(t, cat, WithMetrics(m), WithBatchSize(100))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), CompressionHeader, "zstd")
+	stream, err := flight.NewFlightServiceClient(conn).DoGet(ctx, &flight.Ticket{Ticket: []byte("demo")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := flight.NewRecordReader(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Release()
+	batches := 0
+	for reader.Next() {
+		batches++
+	}
+	if err := reader.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if batches != 10 {
+		t.Fatalf("read %d batches, want 10", batches)
+	}
+
+	for _, name := range []string{"batch_serialization_seconds", "batch_compression_seconds"} {
+		if got := histogramCount(t, m, name, string(arrow.CodecZstd)); got != 10 {
+			t.Errorf("%s has %d zstd observations, want 10", name, got)
+		}
+	}
+}
+
+// emptyService returns a schema and no record batches.
+type emptyService struct{}
+
+func (emptyService) GetData(ctx context.Context, opts arrow.ReadOptions) (array.RecordReader, error) {
+	schema := arrowgo.NewSchema([]arrowgo.Field{{Name: "id", Type: arrowgo.PrimitiveTypes.Int64}}, nil)
+	return array.NewRecordReader(schema, nil)
+}
+
+// failingSendStream fails every message the handler sends.
+type failingSendStream struct {
+	grpc.ServerStream
+}
+
+func (failingSendStream) SendMsg(any) error {
+	return status.Error(codes.Unavailable, "send failed")
+}
+
+func TestFlightDoGetCloseError(t *testing.T) {
+	intercept, errs := handlerErrors()
+	failSends := WithStreamInterceptors(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, failingSendStream{ss})
+	})
+	conn := startTestServer(t, testCatalog(t, map[string]arrow.ArrowService{"empty": emptyService{}}), intercept, failSends)
+
+	stream, err := flight.NewFlightServiceClient(conn).DoGet(context.Background(), &flight.Ticket{Ticket: []byte("empty")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream.Recv()
+
+	// With no batches the schema is only sent when the writer closes
+	select {
+	case err := <-errs:
+		if got := status.Code(err); got != codes.Unavailable {
+			t.Errorf("DoGet returned %s, want %s (%v)", got, codes.Unavailable, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the stream did not end")
+	}
+}
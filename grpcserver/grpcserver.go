@@ -1,82 +1,482 @@
 package grpcserver
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"net"
-	"os"
-	"os/signal"
-	"syscall"
+	"sync"
+	"time"
 
 	"github.com/TFMV/ArrowLink/arrow"
+	"github.com/TFMV/ArrowLink/auth"
+	"github.com/TFMV/ArrowLink/metrics"
 	pb "github.com/TFMV/ArrowLink/proto/dataexchange"
+	arrowgo "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
-	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
-	grpc_zap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
-	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
-// Server implements the ArrowDataServiceServer interface and holds an arrow service instance.
+// Server implements the ArrowDataServiceServer interface and serves the datasets of a catalog.
 type Server struct {
 	pb.ArrowDataServiceServer
-	logger       *zap.Logger
-	arrowService arrow.ArrowService
+	logger      *zap.Logger
+	catalog     *arrow.Catalog
+	sink        arrow.ArrowSink
+	batchSize   int
+	compression arrow.Codec
+	tls         TLSConfig
+	authn       auth.Authenticator
+	policy      *auth.Policy
+	metrics     *metrics.Metrics
+	mem         memory.Allocator
+
+	healthInterval time.Duration
+
+	address            string
+	listener           net.Listener
+	serverOpts         []grpc.ServerOption
+	unaryInterceptors  []grpc.UnaryServerInterceptor
+	streamInterceptors []grpc.StreamServerInterceptor
+	maxRecvMsgSize     int
+	maxSendMsgSize     int
+	keepalive          *KeepaliveConfig
+
+	// Set by Start.
+	mu         sync.Mutex
+	grpcServer *grpc.Server
+	health     *health.Server
+	lis        net.Listener
+	cancel     context.CancelFunc
+	done       chan struct{}
+	serveErr   error
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithSink sets the sink that receives batches uploaded through SendArrowData.
+// By default uploads are decoded, counted and discarded.
+func WithSink(sink arrow.ArrowSink) Option {
+	return func(s *Server) {
+		s.sink = sink
+	}
 }
 
-// NewServer creates a new Server instance.
-func NewServer(logger *zap.Logger, arrowService arrow.ArrowService) *Server {
-	return &Server{
-		logger:       logger,
-		arrowService: arrowService,
+// WithBatchSize sets the maximum number of rows sent in each ArrowData message.
+func WithBatchSize(rows int) Option {
+	return func(s *Server) {
+		s.batchSize = rows
 	}
 }
 
-// GetArrowData retrieves the Arrow data and streams it to the client.
-func (s *Server) GetArrowData(req *pb.Empty, stream pb.ArrowDataService_GetArrowDataServer) error {
-	data, err := s.arrowService.GetData()
+// NewServer creates a new Server instance. Call Start to serve it, or
+// register it with a grpc.Server of your own.
+func NewServer(logger *zap.Logger, catalog *arrow.Catalog, opts ...Option) *Server {
+	s := &Server{
+		logger:      logger,
+		catalog:     catalog,
+		sink:        arrow.NewDiscardSink(nil),
+		batchSize:   arrow.DefaultBatchSize,
+		compression: arrow.CodecNone,
+		mem:         memory.NewGoAllocator(),
+
+		healthInterval: DefaultHealthCheckInterval,
+		address:        DefaultAddress,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// GetArrowData streams the requested dataset to the client, one record batch
+// per ArrowData message, after applying the request's filter and column
+// projection; if no rows match, a single empty batch is sent. By default each
+// payload is a self-contained IPC stream, while FRAMING_MESSAGES sends the
+// schema only once (see messageFramer). The response header reports the
+// framing and the codec compressing the body buffers. When the dataset can
+// resume streams, the payload completing each batch carries a resume token,
+// and a request with that token continues after the batch. Generation stops
+// as soon as the client cancels or its deadline passes, and the trailer
+// reports the time spent and the data sent; see arrow.StreamStats.
+func (s *Server) GetArrowData(req *pb.DataRequest, stream pb.ArrowDataService_GetArrowDataServer) error {
+	ctx := stream.Context()
+
+	ds, err := s.dataset(ctx, req.GetDataset())
 	if err != nil {
-		s.logger.Error("failed to get arrow data", zap.Error(err))
 		return err
 	}
-	return stream.Send(&pb.ArrowData{Payload: data})
+	codec, err := s.negotiateCompression(ctx, req.GetCompression(), req.GetCompressionLevel())
+	if err != nil {
+		return err
+	}
+
+	query := arrow.Query{Columns: req.GetColumns(), Filter: req.GetFilter()}
+	reader, position, err := s.readResumable(ctx, ds, arrow.ReadOptions{BatchSize: s.batchSize, Allocator: s.mem}, query, req.GetResumeToken())
+	if err != nil {
+		return err
+	}
+	defer reader.Release()
+
+	framing := req.GetFraming()
+	if err := stream.SetHeader(metadata.Pairs(arrow.FramingHeader, framingName(framing))); err != nil {
+		return err
+	}
+	if err := reportCompression(stream, codec); err != nil {
+		return err
+	}
+
+	method, _ := grpc.Method(ctx)
+	streamed := s.metrics.Stream(method, ds.Name, metrics.Sent)
+	defer streamed.Done()
+
+	var stats streamStats
+	defer func() {
+		stream.SetTrailer(metadata.MD(arrow.StreamStats(stats).Metadata()))
+	}()
+	send := func(data *pb.ArrowData) error {
+		_, span := tracer.Start(ctx, "Send", trace.WithAttributes(attribute.Int("arrow.bytes", len(data.Payload))))
+		err := stream.Send(data)
+		endSpan(span, err)
+		return err
+	}
+
+	serialize := func(record arrowgo.Record) (encodedBatch, arrow.SerializeTimings, error) {
+		payload, timings, err := arrow.SerializeRecordTimed(record, codec)
+		return encodedBatch{payload: payload, bytes: len(payload), resumeToken: position.advance(record.NumRows())}, timings, err
+	}
+	var framer *messageFramer
+	if framing == pb.Framing_FRAMING_MESSAGES {
+		encoder := arrow.NewMessageEncoder(reader.Schema(), codec, s.mem)
+		defer encoder.Close()
+		framer = &messageFramer{schema: encoder.Schema()}
+		stats.Bytes += int64(len(framer.schema))
+		serialize = func(record arrowgo.Record) (encodedBatch, arrow.SerializeTimings, error) {
+			messages, timings, err := encoder.Encode(record)
+			b := encodedBatch{messages: messages, resumeToken: position.advance(record.NumRows())}
+			for _, m := range messages {
+				b.bytes += len(m.Data)
+			}
+			return b, timings, err
+		}
+	}
+	encoded, stop := s.encodeAhead(ctx, ds.Name, reader, codec, serialize, true, nil)
+	defer stop()
+
+	for b := range encoded {
+		if b.err != nil {
+			return b.err
+		}
+		stats.add(b)
+		streamed.Add(1, b.rows, b.bytes)
+		payloads := [][]byte{b.payload}
+		if framer != nil {
+			payloads = framer.frame(b.messages)
+		}
+		// The token goes with the payload that completes the batch
+		for i, payload := range payloads {
+			data := &pb.ArrowData{Payload: payload}
+			if i == len(payloads)-1 {
+				data.ResumeToken = b.resumeToken
+			}
+			if err := send(data); err != nil {
+				return err
+			}
+		}
+	}
+	// The encoder stops without an error once ctx is done
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return nil
 }
 
-// StartGRPCServer sets up and runs the gRPC server with middleware and graceful shutdown.
-func StartGRPCServer(address string, logger *zap.Logger, arrowService arrow.ArrowService) {
-	grpc_zap.ReplaceGrpcLogger(logger)
+func framingName(framing pb.Framing) string {
+	if framing == pb.Framing_FRAMING_MESSAGES {
+		return arrow.FramingMessages
+	}
+	return arrow.FramingStream
+}
+
+// messageFramer lays out IPC messages in the payloads of FRAMING_MESSAGES:
+// the schema and the first batch's dictionaries go in the first payload, and
+// every batch after that in a payload of its own, after the dictionaries it
+// needs.
+type messageFramer struct {
+	schema     []byte
+	schemaSent bool
+}
 
-	opts := []grpc.ServerOption{
-		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
-			grpc_zap.StreamServerInterceptor(logger),
-			grpc_recovery.StreamServerInterceptor(),
-		)),
-		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
-			grpc_zap.UnaryServerInterceptor(logger),
-			grpc_recovery.UnaryServerInterceptor(),
-		)),
+// frame returns the payloads that carry the messages of one batch.
+func (f *messageFramer) frame(messages []arrow.IPCMessage) [][]byte {
+	var parts [][]byte
+	for _, m := range messages {
+		parts = append(parts, m.Data)
 	}
+	if f.schemaSent {
+		return [][]byte{bytes.Join(parts, nil)}
+	}
+	f.schemaSent = true
+	dicts, batch := parts[:len(parts)-1], parts[len(parts)-1]
+	return [][]byte{bytes.Join(append([][]byte{f.schema}, dicts...), nil), batch}
+}
 
-	grpcServer := grpc.NewServer(opts...)
-	pb.RegisterArrowDataServiceServer(grpcServer, NewServer(logger, arrowService))
+// encodedBatch is a serialized record batch and the time it took to produce,
+// or the error that ended the stream. It holds either a self-contained
+// payload or the IPC messages of the batch, depending on the protocol, and
+// the token resuming the stream after it, if the dataset allows it.
+type encodedBatch struct {
+	payload       []byte
+	messages      []arrow.IPCMessage
+	resumeToken   []byte
+	bytes         int
+	rows          int64
+	generation    time.Duration
+	serialization time.Duration
+	compression   time.Duration
+	err           error
+}
 
-	lis, err := net.Listen("tcp", address)
-	if err != nil {
-		logger.Fatal("failed to listen", zap.Error(err))
+// streamStats accumulates the encoded batches of a stream.
+type streamStats arrow.StreamStats
+
+func (s *streamStats) add(b encodedBatch) {
+	s.Generation += b.generation
+	s.Serialization += b.serialization
+	s.Compression += b.compression
+	s.Rows += b.rows
+	s.Batches++
+	s.Bytes += int64(b.bytes)
+}
+
+// serializeFunc encodes a record batch, setting the payload or messages and
+// the size of the returned batch.
+type serializeFunc func(record arrowgo.Record) (encodedBatch, arrow.SerializeTimings, error)
+
+// observeSerialization records the time spent serializing a batch in the
+// serialization and compression histograms, and returns the part spent
+// compressing, if any.
+func (s *Server) observeSerialization(codec arrow.Codec, timings arrow.SerializeTimings) (compression time.Duration) {
+	s.metrics.ObserveSerialization(string(codec), timings.Total)
+	if codec == arrow.CodecNone {
+		return 0
 	}
+	s.metrics.ObserveCompression(string(codec), timings.Encode)
+	return timings.Encode
+}
 
-	// Run server in a separate goroutine.
+// encodeAhead encodes the batches of reader on a separate goroutine, one
+// ahead of the caller, so that serialization overlaps with the network. If
+// demand is not nil, each batch is only read once a value is received from
+// it. The caller must call stop once it stops receiving.
+func (s *Server) encodeAhead(ctx context.Context, dataset string, reader array.RecordReader, codec arrow.Codec, serialize serializeFunc, sendEmpty bool, demand <-chan struct{}) (encoded <-chan encodedBatch, stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	out := make(chan encodedBatch, 1)
 	go func() {
-		logger.Info("gRPC server is running", zap.String("address", address))
-		if err := grpcServer.Serve(lis); err != nil {
-			logger.Fatal("failed to serve", zap.Error(err))
-		}
+		defer close(out)
+		s.encodeBatches(ctx, dataset, reader, codec, serialize, sendEmpty, demand, out)
 	}()
+	return out, func() {
+		cancel()
+		for range out {
+		}
+	}
+}
+
+// encodeBatches serializes every batch of reader and delivers it to out
+// until the reader is exhausted or ctx is done. If no batch is read and
+// sendEmpty is set, a single empty batch carries the schema. See encodeAhead
+// for demand.
+func (s *Server) encodeBatches(ctx context.Context, dataset string, reader array.RecordReader, codec arrow.Codec, serialize serializeFunc, sendEmpty bool, demand <-chan struct{}, out chan<- encodedBatch) {
+	deliver := func(b encodedBatch) bool {
+		select {
+		case out <- b:
+			return b.err == nil
+		case <-ctx.Done():
+			return false
+		}
+	}
+	encode := func(record arrowgo.Record, generation time.Duration) bool {
+		_, span := tracer.Start(ctx, "EncodeBatch", trace.WithAttributes(
+			attribute.String("arrow.codec", string(codec)),
+			attribute.Int64("arrow.rows", record.NumRows()),
+		))
+		b, timings, err := serialize(record)
+		if err != nil {
+			endSpan(span, err)
+			s.logger.Error("failed to serialize arrow data", zap.Error(err))
+			return deliver(encodedBatch{err: toStatus(err)})
+		}
+		span.SetAttributes(attribute.Int("arrow.bytes", b.bytes))
+		span.End()
+		b.rows, b.generation, b.serialization = record.NumRows(), generation, timings.Total
+		b.compression = s.observeSerialization(codec, timings)
+		return deliver(b)
+	}
+
+	sent := 0
+	// waited is the time spent in Next since the last batch was encoded.
+	var waited time.Duration
+	for {
+		if demand != nil {
+			select {
+			case <-demand:
+			case <-ctx.Done():
+				return
+			}
+		}
+		start := time.Now()
+		ok := reader.Next()
+		waited += time.Since(start)
+		if !ok {
+			break
+		}
+		s.metrics.ObserveGeneration(dataset, time.Since(start))
+		if !encode(reader.Record(), waited) {
+			return
+		}
+		waited = 0
+		sent++
+	}
+	if err := reader.Err(); err != nil {
+		if ctx.Err() == nil {
+			s.logger.Error("failed to get arrow data", zap.Error(err))
+		}
+		deliver(encodedBatch{err: toStatus(err)})
+		return
+	}
+	if sent == 0 && sendEmpty {
+		empty := array.NewRecord(reader.Schema(), emptyColumns(s.mem, reader.Schema()), 0)
+		defer empty.Release()
+		encode(empty, waited)
+	}
+}
+
+func emptyColumns(mem memory.Allocator, schema *arrowgo.Schema) []arrowgo.Array {
+	cols := make([]arrowgo.Array, schema.NumFields())
+	for i, f := range schema.Fields() {
+		cols[i] = array.MakeArrayOfNull(mem, f.Type, 0)
+	}
+	return cols
+}
+
+// toStatus converts an error into a gRPC status error. Context errors map to
+// Canceled and DeadlineExceeded, invalid queries to InvalidArgument, errors
+// that already carry a status are kept, and everything else is reported as
+// Internal.
+func toStatus(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	if errors.Is(err, arrow.ErrInvalidQuery) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// SendArrowData receives IPC payloads from the client, validates them against
+// the sink's schema and forwards every record batch to the sink. Payloads that
+// cannot be decoded are rejected and reported in the Ack, and the rest of the
+// stream is still processed. A payload that does not match the schema ends
+// the upload with InvalidArgument, as in DoPut, since the batches after it
+// would not match either.
+func (s *Server) SendArrowData(stream pb.ArrowDataService_SendArrowDataServer) error {
+	method, _ := grpc.Method(stream.Context())
+	received := s.metrics.Stream(method, "", metrics.Received)
+	defer received.Done()
+
+	ack := &pb.Ack{}
+	for n := 0; ; n++ {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			ack.Message = fmt.Sprintf("received %d rows in %d batches", ack.Rows, ack.Batches)
+			return stream.SendAndClose(ack)
+		}
+		if err != nil {
+			return err
+		}
+		records, err := s.decodePayload(msg.Payload)
+		if errors.Is(err, arrow.ErrSchemaMismatch) {
+			return status.Errorf(codes.InvalidArgument, "payload %d: %v", n, err)
+		}
+		if err != nil {
+			s.logger.Warn("rejected arrow payload", zap.Int("payload", n), zap.Error(err))
+			ack.RejectedPayloads++
+			ack.Errors = append(ack.Errors, fmt.Sprintf("payload %d: %v", n, err))
+			continue
+		}
+
+		err = s.writeRecords(records)
+		if err != nil {
+			s.logger.Error("failed to write arrow data", zap.Int("payload", n), zap.Error(err))
+			return status.Errorf(codes.Internal, "payload %d: %v", n, err)
+		}
+		var rows int64
+		for _, rec := range records {
+			rows += rec.NumRows()
+			ack.Batches++
+		}
+		ack.Rows += rows
+		ack.Bytes += int64(len(msg.Payload))
+		received.Add(len(records), rows, len(msg.Payload))
+	}
+}
+
+// decodePayload reads every record batch from a serialized IPC stream. The
+// whole payload is decoded before anything reaches the sink so that a corrupt
+// payload is rejected as a unit.
+func (s *Server) decodePayload(payload []byte) ([]arrowgo.Record, error) {
+	reader, err := ipc.NewReader(bytes.NewReader(payload), ipc.WithAllocator(s.mem))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Release()
+
+	if err := arrow.CheckSchema(s.sink.Schema(), reader.Schema()); err != nil {
+		return nil, err
+	}
 
-	// Listen for termination signals to gracefully shut down.
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
-	<-quit
-	logger.Info("shutting down gRPC server...")
-	grpcServer.GracefulStop()
-	logger.Info("gRPC server shutdown complete")
+	var records []arrowgo.Record
+	for reader.Next() {
+		rec := reader.Record()
+		rec.Retain()
+		records = append(records, rec)
+	}
+	if err := reader.Err(); err != nil {
+		releaseRecords(records)
+		return nil, err
+	}
+	return records, nil
+}
+
+func (s *Server) writeRecords(records []arrowgo.Record) error {
+	defer releaseRecords(records)
+	for _, rec := range records {
+		if err := s.sink.Write(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func releaseRecords(records []arrowgo.Record) {
+	for _, rec := range records {
+		rec.Release()
+	}
 }
@@ -0,0 +1,175 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TFMV/ArrowLink/arrow"
+	pb "github.com/TFMV/ArrowLink/proto/dataexchange"
+	pbv2 "github.com/TFMV/ArrowLink/proto/dataexchange/v2"
+	arrowgo "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startTestServer serves cat over an in-memory connection until the test
+// ends and returns a client connection to it.
+func startTestServer(t *testing.T, cat *arrow.Catalog, opts ...Option) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := NewServer(zap.NewNop(), cat, append([]Option{WithListener(lis)}, opts...)...)
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		s.Stop(ctx)
+	})
+	return conn
+}
+
+// testCatalog registers the given services under their names.
+func testCatalog(t *testing.T, services map[string]arrow.ArrowService) *arrow.Catalog {
+	t.Helper()
+	cat := arrow.NewCatalog()
+	for name, svc := range services {
+		if err := cat.Register(arrow.Dataset{Name: name, Service: svc}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return cat
+}
+
+// stallingService sends one batch and then blocks until the read is
+// cancelled, like a slow source.
+type stallingService struct{}
+
+func (stallingService) GetData(ctx context.Context, opts arrow.ReadOptions) (array.RecordReader, error) {
+	schema := arrowgo.NewSchema([]arrowgo.Field{{Name: "id", Type: arrowgo.PrimitiveTypes.Int64}}, nil)
+	builder := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer builder.Release()
+	builder.Field(0).(*array.Int64Builder).Append(1)
+	record := builder.NewRecord()
+	defer record.Release()
+	reader, err := array.NewRecordReader(schema, []arrowgo.Record{record})
+	if err != nil {
+		return nil, err
+	}
+	return &stallingReader{RecordReader: reader, ctx: ctx}, nil
+}
+
+type stallingReader struct {
+	array.RecordReader
+	ctx context.Context
+}
+
+func (r *stallingReader) Next() bool {
+	if r.RecordReader.Next() {
+		return true
+	}
+	<-r.ctx.Done()
+	return false
+}
+
+func (r *stallingReader) Err() error {
+	return r.ctx.Err()
+}
+
+// handlerErrors records the error each stream handler returns, which is the
+// status that interceptors and clients see.
+func handlerErrors() (Option, <-chan error) {
+	errs := make(chan error, 1)
+	return WithStreamInterceptors(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		errs <- err
+		return err
+	}), errs
+}
+
+// streamTimeout gives every stream handler a deadline of its own. A client
+// deadline would not do: the client cancels the call when it passes, which
+// can reach the server before the server's copy of the deadline expires.
+func streamTimeout(d time.Duration) Option {
+	return WithStreamInterceptors(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := context.WithTimeout(ss.Context(), d)
+		defer cancel()
+		wrapped := grpc_middleware.WrapServerStream(ss)
+		wrapped.WrappedContext = ctx
+		return handler(srv, wrapped)
+	})
+}
+
+func TestGetArrowDataStopped(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		want codes.Code
+	}{
+		{"v1 cancelled", codes.Canceled},
+		{"v1 deadline", codes.DeadlineExceeded},
+		{"v2 cancelled", codes.Canceled},
+		{"v2 deadline", codes.DeadlineExceeded},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			intercept, errs := handlerErrors()
+			opts := []Option{intercept}
+			if tt.want == codes.DeadlineExceeded {
+				opts = append(opts, streamTimeout(100*time.Millisecond))
+			}
+			conn := startTestServer(t, testCatalog(t, map[string]arrow.ArrowService{"slow": stallingService{}}), opts...)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var recv func() error
+			if strings.HasPrefix(tt.name, "v1") {
+				stream, err := pb.NewArrowDataServiceClient(conn).GetArrowData(ctx, &pb.DataRequest{Dataset: "slow"})
+				if err != nil {
+					t.Fatal(err)
+				}
+				recv = func() error { _, err := stream.Recv(); return err }
+			} else {
+				stream, err := pbv2.NewArrowDataServiceClient(conn).GetArrowData(ctx, &pbv2.DataRequest{Dataset: "slow"})
+				if err != nil {
+					t.Fatal(err)
+				}
+				recv = func() error { _, err := stream.Recv(); return err }
+			}
+			// The first batch arrives before the source stalls
+			if err := recv(); err != nil {
+				t.Fatal(err)
+			}
+			if tt.want == codes.Canceled {
+				cancel()
+			}
+
+			select {
+			case err := <-errs:
+				if got := status.Code(err); got != tt.want {
+					t.Errorf("the server ended the stream with %s, want %s (%v)", got, tt.want, err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("the stream did not end")
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/TFMV/ArrowLink/arrow"
+	pb "github.com/TFMV/ArrowLink/proto/dataexchange"
+	pbv2 "github.com/TFMV/ArrowLink/proto/dataexchange/v2"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// DefaultHealthCheckInterval is how often datasets are checked unless
+// WithHealthCheckInterval says otherwise.
+const DefaultHealthCheckInterval = 30 * time.Second
+
+// healthCheckTimeout bounds how long a dataset may take to produce its schema.
+const healthCheckTimeout = 10 * time.Second
+
+// DatasetHealthService returns the name under which the health service
+// reports on a dataset, e.g. "arrowlink.dataset/demo".
+func DatasetHealthService(dataset string) string {
+	return "arrowlink.dataset/" + dataset
+}
+
+// servedServices are the gRPC services whose health follows the datasets'.
+var servedServices = []string{
+	"", // the server as a whole
+	pb.ArrowDataService_ServiceDesc.ServiceName,
+	pbv2.ArrowDataService_ServiceDesc.ServiceName,
+	flightServiceName,
+}
+
+// flightServiceName is the full name of the Arrow Flight service.
+const flightServiceName = "arrow.flight.protocol.FlightService"
+
+// WithHealthCheckInterval sets how often every dataset is checked to update
+// the health service. Zero checks them once at startup.
+func WithHealthCheckInterval(d time.Duration) Option {
+	return func(s *Server) {
+		s.healthInterval = d
+	}
+}
+
+// checkHealth sets the status of each dataset according to whether its
+// service can produce a schema and passes its own health check, if any. The
+// gRPC services are SERVING only while every dataset is.
+func (s *Server) checkHealth(ctx context.Context, hs *health.Server) {
+	serving := true
+	for _, ds := range s.catalog.List() {
+		status := healthpb.HealthCheckResponse_SERVING
+		if err := s.checkDataset(ctx, ds); err != nil {
+			s.logger.Warn("dataset is unhealthy", zap.String("dataset", ds.Name), zap.Error(err))
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+			serving = false
+		}
+		hs.SetServingStatus(DatasetHealthService(ds.Name), status)
+	}
+
+	status := healthpb.HealthCheckResponse_SERVING
+	if !serving {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	for _, name := range servedServices {
+		hs.SetServingStatus(name, status)
+	}
+}
+
+func (s *Server) checkDataset(ctx context.Context, ds arrow.Dataset) error {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	return ds.CheckHealth(ctx)
+}
+
+// watchHealth checks the datasets now and then every health check interval
+// until ctx is done.
+func (s *Server) watchHealth(ctx context.Context, hs *health.Server) {
+	s.checkHealth(ctx, hs)
+	if s.healthInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.healthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkHealth(ctx, hs)
+		}
+	}
+}
+
+// newHealthServer creates a health service reporting NOT_SERVING until the
+// first check completes.
+func newHealthServer() *health.Server {
+	hs := health.NewServer()
+	for _, name := range servedServices {
+		hs.SetServingStatus(name, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	return hs
+}
@@ -0,0 +1,292 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/TFMV/ArrowLink/arrow"
+	pb "github.com/TFMV/ArrowLink/proto/dataexchange"
+	pbv2 "github.com/TFMV/ArrowLink/proto/dataexchange/v2"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.uber.org/zap"
+
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	grpc_zap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
+	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
+)
+
+// DefaultAddress is the address a Server listens on unless WithAddress or
+// WithListener says otherwise.
+const DefaultAddress = ":50051"
+
+var (
+	// ErrServerStarted is returned by Start when the server is already
+	// running or has been stopped.
+	ErrServerStarted = errors.New("grpcserver: server already started")
+	// ErrServerNotStarted is returned by Stop when Start has not succeeded.
+	ErrServerNotStarted = errors.New("grpcserver: server not started")
+)
+
+// WithAddress sets the TCP address to listen on, e.g. ":50051" or
+// "127.0.0.1:0" for an ephemeral port.
+func WithAddress(address string) Option {
+	return func(s *Server) {
+		s.address = address
+	}
+}
+
+// WithListener serves on lis instead of listening on an address. The server
+// closes it when it stops.
+func WithListener(lis net.Listener) Option {
+	return func(s *Server) {
+		s.listener = lis
+	}
+}
+
+// WithServerOptions passes additional options to grpc.NewServer, after the
+// server's own.
+func WithServerOptions(opts ...grpc.ServerOption) Option {
+	return func(s *Server) {
+		s.serverOpts = append(s.serverOpts, opts...)
+	}
+}
+
+// WithMaxMessageSize sets the largest message the server receives and sends.
+// Zero keeps gRPC's default: 4 MiB received and no limit on sends.
+func WithMaxMessageSize(recv, send int) Option {
+	return func(s *Server) {
+		s.maxRecvMsgSize = recv
+		s.maxSendMsgSize = send
+	}
+}
+
+// KeepaliveConfig controls HTTP/2 keepalive pings between the server and its
+// clients. Zero values keep gRPC's defaults.
+type KeepaliveConfig struct {
+	// Time is how long a connection may be idle before the server pings the
+	// client, and Timeout how long it waits for the reply before closing it.
+	Time    time.Duration
+	Timeout time.Duration
+	// MinTime is the shortest interval at which clients may ping; clients
+	// that ping more often are disconnected. PermitWithoutStream also allows
+	// pings on connections without active calls.
+	MinTime             time.Duration
+	PermitWithoutStream bool
+}
+
+// WithKeepalive sets the keepalive parameters and enforcement policy.
+func WithKeepalive(cfg KeepaliveConfig) Option {
+	return func(s *Server) {
+		s.keepalive = &cfg
+	}
+}
+
+// WithUnaryInterceptors adds unary interceptors, which run after the built-in
+// logging, recovery, metrics and authentication interceptors.
+func WithUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) Option {
+	return func(s *Server) {
+		s.unaryInterceptors = append(s.unaryInterceptors, interceptors...)
+	}
+}
+
+// WithStreamInterceptors adds stream interceptors, which run after the
+// built-in logging, recovery, metrics and authentication interceptors.
+func WithStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) Option {
+	return func(s *Server) {
+		s.streamInterceptors = append(s.streamInterceptors, interceptors...)
+	}
+}
+
+// Start listens and starts serving the ArrowDataService, the Arrow Flight
+// service, health checks and reflection in the background. It returns once
+// the server accepts connections; ctx only bounds the time spent listening.
+// A Server can be started once.
+func (s *Server) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done != nil {
+		return ErrServerStarted
+	}
+
+	grpcServer, err := s.newGRPCServer()
+	if err != nil {
+		return err
+	}
+	lis := s.listener
+	if lis == nil {
+		var lc net.ListenConfig
+		if lis, err = lc.Listen(ctx, "tcp", s.address); err != nil {
+			return err
+		}
+	}
+
+	healthCtx, cancel := context.WithCancel(context.Background())
+	s.grpcServer = grpcServer
+	s.lis = lis
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go s.watchHealth(healthCtx, s.health)
+	go func() {
+		defer close(s.done)
+		// Serve reports ErrServerStopped if Stop comes first
+		if err := grpcServer.Serve(lis); !errors.Is(err, grpc.ErrServerStopped) {
+			s.serveErr = err
+		}
+	}()
+	s.logger.Info("gRPC server is running", zap.Stringer("address", lis.Addr()), zap.Bool("tls", s.tls.Enabled()))
+	return nil
+}
+
+// newGRPCServer creates the gRPC server with its interceptors and services.
+func (s *Server) newGRPCServer() (*grpc.Server, error) {
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		grpc_zap.StreamServerInterceptor(s.logger),
+		grpc_recovery.StreamServerInterceptor(),
+	}
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		grpc_zap.UnaryServerInterceptor(s.logger),
+		grpc_recovery.UnaryServerInterceptor(),
+	}
+	if s.metrics != nil {
+		streamInterceptors = append(streamInterceptors, s.metrics.StreamServerInterceptor())
+		unaryInterceptors = append(unaryInterceptors, s.metrics.UnaryServerInterceptor())
+	}
+	if s.authn != nil {
+		streamInterceptors = append(streamInterceptors, s.authStreamInterceptor())
+		unaryInterceptors = append(unaryInterceptors, s.authUnaryInterceptor())
+	}
+	streamInterceptors = append(streamInterceptors, s.streamInterceptors...)
+	unaryInterceptors = append(unaryInterceptors, s.unaryInterceptors...)
+
+	serverOpts := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(streamInterceptors...)),
+		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(unaryInterceptors...)),
+	}
+	if s.maxRecvMsgSize > 0 {
+		serverOpts = append(serverOpts, grpc.MaxRecvMsgSize(s.maxRecvMsgSize))
+	}
+	if s.maxSendMsgSize > 0 {
+		serverOpts = append(serverOpts, grpc.MaxSendMsgSize(s.maxSendMsgSize))
+	}
+	if s.keepalive != nil {
+		serverOpts = append(serverOpts,
+			grpc.KeepaliveParams(keepalive.ServerParameters{Time: s.keepalive.Time, Timeout: s.keepalive.Timeout}),
+			grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+				MinTime:             s.keepalive.MinTime,
+				PermitWithoutStream: s.keepalive.PermitWithoutStream,
+			}),
+		)
+	}
+	if s.tls.Enabled() {
+		creds, err := ServerCredentials(s.tls, s.logger)
+		if err != nil {
+			return nil, err
+		}
+		serverOpts = append(serverOpts, grpc.Creds(creds))
+	}
+	serverOpts = append(serverOpts, s.serverOpts...)
+
+	grpcServer := grpc.NewServer(serverOpts...)
+	pb.RegisterArrowDataServiceServer(grpcServer, s)
+	pbv2.RegisterArrowDataServiceServer(grpcServer, NewV2Server(s))
+	flight.RegisterFlightServiceServer(grpcServer, NewFlightServer(s))
+	s.health = newHealthServer()
+	healthpb.RegisterHealthServer(grpcServer, s.health)
+	reflection.Register(grpcServer)
+	return grpcServer, nil
+}
+
+// Addr returns the address the server listens on, or nil before Start.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lis == nil {
+		return nil
+	}
+	return s.lis.Addr()
+}
+
+// Stop shuts the server down gracefully: health checks report NOT_SERVING so
+// that load balancers stop routing to it, new calls are refused and calls in
+// progress run to completion. If ctx is done first, the remaining calls are
+// cancelled and ctx's error is returned.
+func (s *Server) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	grpcServer, hs, done := s.grpcServer, s.health, s.done
+	s.mu.Unlock()
+	if grpcServer == nil {
+		return ErrServerNotStarted
+	}
+
+	s.logger.Info("shutting down gRPC server...")
+	hs.Shutdown()
+	s.cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+		<-done
+		return ctx.Err()
+	}
+	<-done
+	s.logger.Info("gRPC server shutdown complete")
+	return nil
+}
+
+// Wait blocks until the server stops serving and returns the error that made
+// it stop, or nil after Stop. It returns ErrServerNotStarted before Start.
+func (s *Server) Wait() error {
+	s.mu.Lock()
+	done := s.done
+	s.mu.Unlock()
+	if done == nil {
+		return ErrServerNotStarted
+	}
+	<-done
+	return s.serveErr
+}
+
+// Run starts the server and serves until ctx is done, then stops it
+// gracefully. It returns early if serving fails.
+func (s *Server) Run(ctx context.Context) error {
+	if err := s.Start(ctx); err != nil {
+		return err
+	}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- s.Wait() }()
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+		return s.Stop(context.Background())
+	}
+}
+
+// StartGRPCServer serves catalog on address until the process receives
+// SIGINT or SIGTERM, then shuts down gracefully. It also routes gRPC's own
+// logs to logger. Use NewServer and Start to embed a server instead.
+func StartGRPCServer(address string, logger *zap.Logger, catalog *arrow.Catalog, opts ...Option) error {
+	grpc_zap.ReplaceGrpcLogger(logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	opts = append([]Option{WithAddress(address)}, opts...)
+	return NewServer(logger, catalog, opts...).Run(ctx)
+}
@@ -0,0 +1,191 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/TFMV/ArrowLink/arrow"
+	pb "github.com/TFMV/ArrowLink/proto/dataexchange"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// gatedService sends one batch and then waits for release to be closed
+// before ending the stream, like a source that takes a while to finish.
+type gatedService struct {
+	release chan struct{}
+}
+
+func (s gatedService) GetData(ctx context.Context, opts arrow.ReadOptions) (array.RecordReader, error) {
+	reader, err := stallingService{}.GetData(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &gatedReader{RecordReader: reader.(*stallingReader).RecordReader, ctx: ctx, release: s.release}, nil
+}
+
+type gatedReader struct {
+	array.RecordReader
+	ctx     context.Context
+	release chan struct{}
+	err     error
+}
+
+func (r *gatedReader) Next() bool {
+	if r.RecordReader.Next() {
+		return true
+	}
+	select {
+	case <-r.release:
+	case <-r.ctx.Done():
+		r.err = r.ctx.Err()
+	}
+	return false
+}
+
+func (r *gatedReader) Err() error {
+	return r.err
+}
+
+// startLifecycleServer starts a server of svc and returns it with a client
+// connection; unlike startTestServer it leaves stopping the server to the
+// test.
+func startLifecycleServer(t *testing.T, svc arrow.ArrowService) (*Server, *grpc.ClientConn) {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := NewServer(zap.NewNop(), testCatalog(t, map[string]arrow.ArrowService{"slow": svc}), WithListener(lis))
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Stop(context.Background()) })
+	return s, dialListener(t, lis)
+}
+
+func dialListener(t *testing.T, lis *bufconn.Listener) *grpc.ClientConn {
+	t.Helper()
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestStopWaitsForStreams(t *testing.T) {
+	release := make(chan struct{})
+	s, conn := startLifecycleServer(t, gatedService{release: release})
+	client := pb.NewArrowDataServiceClient(conn)
+	stream, err := client.GetArrowData(context.Background(), &pb.DataRequest{Dataset: "slow"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- s.Stop(context.Background()) }()
+	select {
+	case err := <-stopped:
+		t.Fatalf("Stop returned %v while a stream was in flight", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	// New calls are refused while the stream drains
+	if _, err := client.ListDatasets(context.Background(), &pb.Empty{}); status.Code(err) != codes.Unavailable {
+		t.Errorf("ListDatasets during shutdown = %v, want Unavailable", err)
+	}
+
+	close(release)
+	if _, err := stream.Recv(); !errors.Is(err, io.EOF) {
+		t.Errorf("the stream ended with %v, want it to complete", err)
+	}
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Errorf("Stop = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return once the stream completed")
+	}
+}
+
+func TestStopDeadlineCancelsStreams(t *testing.T) {
+	s, conn := startLifecycleServer(t, stallingService{})
+	stream, err := pb.NewArrowDataServiceClient(conn).GetArrowData(context.Background(), &pb.DataRequest{Dataset: "slow"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := s.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Stop = %v, want the deadline's error", err)
+	}
+	if _, err := stream.Recv(); err == nil || errors.Is(err, io.EOF) {
+		t.Errorf("the stream ended with %v, want it cut off", err)
+	}
+}
+
+func TestStopTwice(t *testing.T) {
+	s := NewServer(zap.NewNop(), testCatalog(t, nil), WithListener(bufconn.Listen(1<<10)))
+	if err := s.Stop(context.Background()); !errors.Is(err, ErrServerNotStarted) {
+		t.Errorf("Stop before Start = %v, want ErrServerNotStarted", err)
+	}
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := s.Stop(context.Background()); err != nil {
+			t.Errorf("Stop #%d = %v", i+1, err)
+		}
+	}
+	if err := s.Wait(); err != nil {
+		t.Errorf("Wait after Stop = %v", err)
+	}
+	if err := s.Start(context.Background()); !errors.Is(err, ErrServerStarted) {
+		t.Errorf("Start after Stop = %v, want ErrServerStarted", err)
+	}
+}
+
+func TestRunStopsOnCancel(t *testing.T) {
+	lis := bufconn.Listen(1 << 20)
+	s := NewServer(zap.NewNop(), testCatalog(t, map[string]arrow.ArrowService{"demo": arrow.NewDemoArrowService(10)}), WithListener(lis))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ran := make(chan error, 1)
+	go func() { ran <- s.Run(ctx) }()
+
+	client := pb.NewArrowDataServiceClient(dialListener(t, lis))
+	if _, err := client.ListDatasets(context.Background(), &pb.Empty{}, grpc.WaitForReady(true)); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	select {
+	case err := <-ran:
+		if err != nil {
+			t.Errorf("Run = %v, want nil after cancel", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after its context was cancelled")
+	}
+	if _, err := client.ListDatasets(context.Background(), &pb.Empty{}); status.Code(err) != codes.Unavailable {
+		t.Errorf("ListDatasets after Run returned = %v, want Unavailable", err)
+	}
+}
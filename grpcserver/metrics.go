@@ -0,0 +1,54 @@
+package grpcserver
+
+import (
+	"time"
+
+	"github.com/TFMV/ArrowLink/metrics"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithMetrics records RPC, streaming and pipeline metrics in m. Batches are
+// allocated through m's counting allocator so the bytes they hold are
+// reported.
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(s *Server) {
+		s.metrics = m
+		s.mem = m.Allocator(s.mem)
+	}
+}
+
+// meteredFlightStream counts the bytes of the Flight messages sent on a DoGet
+// stream and the time spent sending them, tracing each Send.
+type meteredFlightStream struct {
+	flight.FlightService_DoGetServer
+	bytes   int
+	sending time.Duration
+}
+
+func (s *meteredFlightStream) Send(data *flight.FlightData) error {
+	size := len(data.DataHeader) + len(data.DataBody)
+	_, span := tracer.Start(s.Context(), "Send", trace.WithAttributes(attribute.Int("arrow.bytes", size)))
+	start := time.Now()
+	err := s.FlightService_DoGetServer.Send(data)
+	s.sending += time.Since(start)
+	s.bytes += size
+	endSpan(span, err)
+	return err
+}
+
+// meteredFlightUpload counts the bytes of the Flight messages received on a
+// DoPut stream.
+type meteredFlightUpload struct {
+	flight.FlightService_DoPutServer
+	bytes int
+}
+
+func (s *meteredFlightUpload) Recv() (*flight.FlightData, error) {
+	data, err := s.FlightService_DoPutServer.Recv()
+	if data != nil {
+		s.bytes += len(data.DataHeader) + len(data.DataBody)
+	}
+	return data, err
+}
@@ -0,0 +1,140 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/TFMV/ArrowLink/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// resumeToken records how far a client got through a stream. It is opaque
+// to clients, which hand back the token of the last batch they received.
+type resumeToken struct {
+	Dataset  string `json:"dataset"`
+	Snapshot string `json:"snapshot"`
+	// Query fingerprints the request options that shape the batches.
+	Query string `json:"query"`
+	// Offset counts the dataset's batches read, before filtering.
+	Offset int64 `json:"offset"`
+	// Batches and Rows count what was sent.
+	Batches int64 `json:"batches"`
+	Rows    int64 `json:"rows"`
+}
+
+// queryFingerprint identifies the options a resumed request must repeat.
+func queryFingerprint(batchSize int, q arrow.Query) string {
+	spec, _ := json.Marshal(struct {
+		BatchSize int
+		Columns   []string
+		Filter    string
+		Limit     int64
+	}{batchSize, q.Columns, q.Filter, q.Limit})
+	sum := sha256.Sum256(spec)
+	return hex.EncodeToString(sum[:12])
+}
+
+// countingReader counts the batches read from a dataset.
+type countingReader struct {
+	array.RecordReader
+	read int64
+}
+
+func (r *countingReader) Next() bool {
+	if !r.RecordReader.Next() {
+		return false
+	}
+	r.read++
+	return true
+}
+
+// resumePosition issues the tokens of a stream as its batches are encoded.
+type resumePosition struct {
+	token  resumeToken
+	offset int64
+	source *countingReader
+}
+
+// advance accounts for a batch of rows and returns the token that resumes
+// the stream after it. Batches are encoded right after they are read, so the
+// source has not been read past the batch yet. A nil position returns nil.
+func (p *resumePosition) advance(rows int64) []byte {
+	if p == nil {
+		return nil
+	}
+	p.token.Offset = p.offset + p.source.read
+	p.token.Batches++
+	p.token.Rows += rows
+	token, _ := json.Marshal(p.token)
+	return token
+}
+
+// sent returns the number of batches sent before this stream, when it
+// resumes another one.
+func (p *resumePosition) sent() int64 {
+	if p == nil {
+		return 0
+	}
+	return p.token.Batches
+}
+
+// readResumable reads ds with the query applied, starting after the batch
+// that carried token, if any. The returned position is nil when ds cannot
+// resume streams; tokens are only issued otherwise.
+func (s *Server) readResumable(ctx context.Context, ds arrow.Dataset, opts arrow.ReadOptions, q arrow.Query, token []byte) (array.RecordReader, *resumePosition, error) {
+	snapshot := ds.Snapshot()
+	start := resumeToken{Dataset: ds.Name, Snapshot: snapshot, Query: queryFingerprint(opts.BatchSize, q)}
+	exhausted := false
+	if len(token) > 0 {
+		var prev resumeToken
+		if err := json.Unmarshal(token, &prev); err != nil || prev.Offset < 0 || prev.Batches < 0 || prev.Rows < 0 {
+			return nil, nil, status.Error(codes.InvalidArgument, "invalid resume token")
+		}
+		switch {
+		case prev.Dataset != ds.Name:
+			return nil, nil, status.Errorf(codes.InvalidArgument, "resume token was issued for dataset %q", prev.Dataset)
+		case snapshot == "":
+			return nil, nil, status.Errorf(codes.FailedPrecondition, "dataset %q cannot resume streams", ds.Name)
+		case prev.Snapshot != snapshot:
+			return nil, nil, status.Errorf(codes.FailedPrecondition, "dataset %q changed since the stream began", ds.Name)
+		case prev.Query != start.Query:
+			return nil, nil, status.Error(codes.InvalidArgument, "resume token was issued for a different request")
+		}
+		start = prev
+		opts.StartBatch = prev.Offset
+		if q.Limit > 0 {
+			q.Limit -= prev.Rows
+			exhausted = q.Limit <= 0
+		}
+	}
+
+	reader, err := ds.Service.GetData(ctx, opts)
+	if err != nil {
+		s.logger.Error("failed to get arrow data", zap.Error(err))
+		return nil, nil, toStatus(err)
+	}
+	source := &countingReader{RecordReader: reader}
+	queried, err := arrow.ApplyQuery(ctx, source, q)
+	if err != nil {
+		reader.Release()
+		return nil, nil, toStatus(err)
+	}
+	if exhausted {
+		schema := queried.Schema()
+		queried.Release()
+		if queried, err = array.NewRecordReader(schema, nil); err != nil {
+			return nil, nil, toStatus(fmt.Errorf("create empty reader: %w", err))
+		}
+	}
+
+	if snapshot == "" {
+		return queried, nil, nil
+	}
+	return queried, &resumePosition{token: start, offset: start.Offset, source: source}, nil
+}
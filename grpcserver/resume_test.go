@@ -0,0 +1,400 @@
+package grpcserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TFMV/ArrowLink/arrow"
+	pb "github.com/TFMV/ArrowLink/proto/dataexchange"
+	pbv2 "github.com/TFMV/ArrowLink/proto/dataexchange/v2"
+	arrowgo "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// resumeRows is the size of every resumable test dataset, read in batches
+// of 100 rows.
+const resumeRows = 1000
+
+// writeCSV writes resumeRows rows of id and name to a file in dir.
+func writeCSV(t *testing.T, dir string) string {
+	t.Helper()
+	var b strings.Builder
+	b.WriteString("id,name\n")
+	for i := 0; i < resumeRows; i++ {
+		fmt.Fprintf(&b, "%d,name-%d\n", i, i)
+	}
+	path := filepath.Join(dir, "rows.csv")
+	if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// writeParquet writes resumeRows rows of id and name to a file in dir, in
+// row groups that do not line up with the batches.
+func writeParquet(t *testing.T, dir string) string {
+	t.Helper()
+	schema := arrowgo.NewSchema([]arrowgo.Field{
+		{Name: "id", Type: arrowgo.PrimitiveTypes.Int64},
+		{Name: "name", Type: arrowgo.BinaryTypes.String},
+	}, nil)
+	builder := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer builder.Release()
+	for i := 0; i < resumeRows; i++ {
+		builder.Field(0).(*array.Int64Builder).Append(int64(i))
+		builder.Field(1).(*array.StringBuilder).Append(fmt.Sprintf("name-%d", i))
+	}
+	record := builder.NewRecord()
+	defer record.Release()
+
+	path := filepath.Join(dir, "rows.parquet")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := pqarrow.NewFileWriter(schema, f, parquet.NewWriterProperties(parquet.WithMaxRowGroupLength(350)), pqarrow.DefaultWriterProps())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteBuffered(record); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// resumeServices returns a service of each kind that can resume streams.
+func resumeServices(t *testing.T) map[string]arrow.ArrowService {
+	t.Helper()
+	dir := t.TempDir()
+	csvService, err := arrow.NewCSVService(writeCSV(t, dir), arrow.CSVOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parquetService, err := arrow.NewParquetService(writeParquet(t, dir), arrow.ParquetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]arrow.ArrowService{
+		"csv":     csvService,
+		"parquet": parquetService,
+		"demo":    seededDemo(t),
+	}
+}
+
+func seededDemo(t *testing.T) arrow.ArrowService {
+	t.Helper()
+	seed := int64(7)
+	spec := arrow.DefaultGeneratorSpec()
+	spec.Seed = &seed
+	service, err := arrow.NewDemoArrowServiceFromSpec(spec, resumeRows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service
+}
+
+// v1Batch is a batch received in a version 1 stream.
+type v1Batch struct {
+	payload []byte
+	token   []byte
+}
+
+// getV1 reads a version 1 stream to the end.
+func getV1(client pb.ArrowDataServiceClient, req *pb.DataRequest) ([]v1Batch, error) {
+	stream, err := client.GetArrowData(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+	var batches []v1Batch
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return batches, nil
+		}
+		if err != nil {
+			return batches, err
+		}
+		batches = append(batches, v1Batch{payload: msg.Payload, token: msg.ResumeToken})
+	}
+}
+
+// getV2 reads a version 2 stream to the end and returns its record batches
+// and summary.
+func getV2(client pbv2.ArrowDataServiceClient, req *pbv2.DataRequest) ([]*pbv2.RecordBatch, *pbv2.Summary, error) {
+	stream, err := client.GetArrowData(context.Background(), req)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		batches []*pbv2.RecordBatch
+		summary *pbv2.Summary
+	)
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return batches, summary, nil
+		}
+		if err != nil {
+			return batches, summary, err
+		}
+		if b := msg.GetRecordBatch(); b != nil {
+			batches = append(batches, b)
+		}
+		if s := msg.GetSummary(); s != nil {
+			summary = s
+		}
+	}
+}
+
+func startResumeServer(t *testing.T, services map[string]arrow.ArrowService) *grpc.ClientConn {
+	t.Helper()
+	return startTestServer(t, testCatalog(t, services), WithBatchSize(100))
+}
+
+func TestResumeV1(t *testing.T) {
+	client := pb.NewArrowDataServiceClient(startResumeServer(t, resumeServices(t)))
+	for _, dataset := range []string{"csv", "parquet", "demo"} {
+		t.Run(dataset, func(t *testing.T) {
+			full, err := getV1(client, &pb.DataRequest{Dataset: dataset})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(full) != 10 {
+				t.Fatalf("got %d batches, want 10", len(full))
+			}
+			for n, b := range full {
+				if len(b.token) == 0 {
+					t.Fatalf("batch %d has no resume token", n)
+				}
+				rest, err := getV1(client, &pb.DataRequest{Dataset: dataset, ResumeToken: b.token})
+				if err != nil {
+					t.Fatalf("resume after batch %d: %v", n, err)
+				}
+				want := full[n+1:]
+				if len(want) == 0 {
+					// A stream with no rows left still sends the schema
+					if len(rest) != 1 || countRows(t, rest[0].payload) != 0 {
+						t.Errorf("resume after the last batch: got %d batches, want one empty batch", len(rest))
+					}
+					continue
+				}
+				if len(rest) != len(want) {
+					t.Fatalf("resume after batch %d: got %d batches, want %d", n, len(rest), len(want))
+				}
+				for i := range rest {
+					if !bytes.Equal(rest[i].payload, want[i].payload) || !bytes.Equal(rest[i].token, want[i].token) {
+						t.Errorf("resume after batch %d: batch %d differs from the original stream", n, i)
+					}
+				}
+			}
+		})
+	}
+}
+
+// countRows counts the rows of a self-contained IPC stream.
+func countRows(t *testing.T, payload []byte) int64 {
+	t.Helper()
+	var rows int64
+	if err := arrow.NewArrowReader(payload).ForEach(func(record arrowgo.Record) error {
+		rows += record.NumRows()
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
+
+func TestResumeV2(t *testing.T) {
+	client := pbv2.NewArrowDataServiceClient(startResumeServer(t, resumeServices(t)))
+	for _, dataset := range []string{"csv", "parquet", "demo"} {
+		t.Run(dataset, func(t *testing.T) {
+			req := &pbv2.DataRequest{Dataset: dataset, BatchSize: 100}
+			full, _, err := getV2(client, req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(full) != 10 {
+				t.Fatalf("got %d batches, want 10", len(full))
+			}
+			for n, b := range full {
+				req.ResumeToken = b.ResumeToken
+				rest, summary, err := getV2(client, req)
+				if err != nil {
+					t.Fatalf("resume after batch %d: %v", n, err)
+				}
+				want := full[n+1:]
+				if len(rest) != len(want) {
+					t.Fatalf("resume after batch %d: got %d batches, want %d", n, len(rest), len(want))
+				}
+				for i := range rest {
+					if !bytes.Equal(rest[i].Data, want[i].Data) || rest[i].Index != want[i].Index {
+						t.Errorf("resume after batch %d: batch %d differs from the original stream", n, i)
+					}
+				}
+				if summary == nil || summary.Batches != int64(len(rest)) {
+					t.Errorf("resume after batch %d: summary %v, want %d batches", n, summary, len(rest))
+				}
+			}
+		})
+	}
+}
+
+func TestResumeWithQuery(t *testing.T) {
+	client := pbv2.NewArrowDataServiceClient(startResumeServer(t, resumeServices(t)))
+	// The filter drops two of the dataset's batches and the limit ends the
+	// stream within the sixth
+	req := &pbv2.DataRequest{Dataset: "csv", BatchSize: 100, Columns: []string{"id"}, Filter: "id < 150 OR id >= 400", Limit: 275}
+	full, _, err := getV2(client, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows int64
+	for _, b := range full {
+		rows += b.Rows
+	}
+	if rows != 275 {
+		t.Fatalf("got %d rows, want 275", rows)
+	}
+
+	for n := range full {
+		req.ResumeToken = full[n].ResumeToken
+		rest, _, err := getV2(client, req)
+		if err != nil {
+			t.Fatalf("resume after batch %d: %v", n, err)
+		}
+		if want := full[n+1:]; len(rest) != len(want) {
+			t.Errorf("resume after batch %d: got %d batches, want %d", n, len(rest), len(want))
+			continue
+		}
+		for i := range rest {
+			if !bytes.Equal(rest[i].Data, full[n+1+i].Data) {
+				t.Errorf("resume after batch %d: batch %d differs from the original stream", n, i)
+			}
+		}
+	}
+}
+
+func TestResumeExhaustedLimit(t *testing.T) {
+	client := pbv2.NewArrowDataServiceClient(startResumeServer(t, resumeServices(t)))
+	req := &pbv2.DataRequest{Dataset: "demo", BatchSize: 100, Limit: 200}
+	full, _, err := getV2(client, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(full) != 2 {
+		t.Fatalf("got %d batches, want 2", len(full))
+	}
+
+	// The last batch used up the limit, so the dataset's later batches
+	// must not be sent
+	req.ResumeToken = full[1].ResumeToken
+	rest, summary, err := getV2(client, req)
+	if err != nil {
+		t.Fatalf("resume after the limit: %v", err)
+	}
+	if len(rest) != 0 {
+		t.Errorf("got %d batches after the limit was reached", len(rest))
+	}
+	if summary == nil || summary.Rows != 0 {
+		t.Errorf("summary %v, want no rows", summary)
+	}
+}
+
+func TestResumeRejected(t *testing.T) {
+	dir := t.TempDir()
+	path := writeCSV(t, dir)
+	csvService, err := arrow.NewCSVService(path, arrow.CSVOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := arrow.NewCSVService(path, arrow.CSVOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := pbv2.NewArrowDataServiceClient(startResumeServer(t, map[string]arrow.ArrowService{
+		"csv":      csvService,
+		"other":    other,
+		"unseeded": arrow.NewDemoArrowService(resumeRows),
+	}))
+	full, _, err := getV2(client, &pbv2.DataRequest{Dataset: "csv", BatchSize: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := full[2].ResumeToken
+
+	foreign, _ := json.Marshal(resumeToken{Dataset: "unseeded", Query: queryFingerprint(100, arrow.Query{})})
+	tests := []struct {
+		name string
+		req  *pbv2.DataRequest
+		want codes.Code
+	}{
+		{"other dataset", &pbv2.DataRequest{Dataset: "other", BatchSize: 100, ResumeToken: token}, codes.InvalidArgument},
+		{"other batch size", &pbv2.DataRequest{Dataset: "csv", BatchSize: 50, ResumeToken: token}, codes.InvalidArgument},
+		{"other filter", &pbv2.DataRequest{Dataset: "csv", BatchSize: 100, Filter: "id > 5", ResumeToken: token}, codes.InvalidArgument},
+		{"other columns", &pbv2.DataRequest{Dataset: "csv", BatchSize: 100, Columns: []string{"id"}, ResumeToken: token}, codes.InvalidArgument},
+		{"other limit", &pbv2.DataRequest{Dataset: "csv", BatchSize: 100, Limit: 10, ResumeToken: token}, codes.InvalidArgument},
+		{"not a token", &pbv2.DataRequest{Dataset: "csv", BatchSize: 100, ResumeToken: []byte("garbage")}, codes.InvalidArgument},
+		{"dataset without snapshots", &pbv2.DataRequest{Dataset: "unseeded", BatchSize: 100, ResumeToken: foreign}, codes.FailedPrecondition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := getV2(client, tt.req)
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("code = %s, want %s (%v)", got, tt.want, err)
+			}
+		})
+	}
+
+	t.Run("changed snapshot", func(t *testing.T) {
+		writeCSV(t, dir)
+		later := time.Now().Add(time.Minute)
+		if err := os.Chtimes(path, later, later); err != nil {
+			t.Fatal(err)
+		}
+		_, _, err := getV2(client, &pbv2.DataRequest{Dataset: "csv", BatchSize: 100, ResumeToken: token})
+		if got := status.Code(err); got != codes.FailedPrecondition {
+			t.Errorf("code = %s, want FailedPrecondition (%v)", got, err)
+		}
+	})
+}
+
+func TestResumeSeededDemoOnAnotherServer(t *testing.T) {
+	first := pbv2.NewArrowDataServiceClient(startResumeServer(t, map[string]arrow.ArrowService{"demo": seededDemo(t)}))
+	full, _, err := getV2(first, &pbv2.DataRequest{Dataset: "demo", BatchSize: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A restarted server, or another replica, builds its own service
+	time.Sleep(10 * time.Millisecond)
+	second := pbv2.NewArrowDataServiceClient(startResumeServer(t, map[string]arrow.ArrowService{"demo": seededDemo(t)}))
+	rest, _, err := getV2(second, &pbv2.DataRequest{Dataset: "demo", BatchSize: 100, ResumeToken: full[3].ResumeToken})
+	if err != nil {
+		t.Fatalf("resume on another server: %v", err)
+	}
+	if len(rest) != 6 {
+		t.Fatalf("got %d batches, want 6", len(rest))
+	}
+	for i := range rest {
+		if !bytes.Equal(rest[i].Data, full[4+i].Data) {
+			t.Errorf("batch %d differs from the original server's", 4+i)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package grpcserver
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/credentials"
+)
+
+// certCheckInterval bounds how often the certificate files are checked for
+// changes. Checks happen during TLS handshakes, so an idle server does no
+// work.
+const certCheckInterval = 5 * time.Second
+
+// TLSConfig locates the server's TLS material on disk. The files are watched
+// for changes and reloaded on the next handshake, so certificates can be
+// rotated without restarting the server.
+type TLSConfig struct {
+	// CertFile and KeyFile hold the PEM-encoded server certificate chain and
+	// private key.
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is a PEM bundle of the CAs trusted to sign client
+	// certificates. When set, clients that present a certificate must
+	// present one signed by these CAs.
+	ClientCAFile string
+	// RequireClientCert rejects clients without a valid certificate
+	// (mutual TLS). It requires ClientCAFile.
+	RequireClientCert bool
+}
+
+// Enabled reports whether TLS is configured.
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
+}
+
+// WithTLS serves over TLS instead of plaintext.
+func WithTLS(cfg TLSConfig) Option {
+	return func(s *Server) {
+		s.tls = cfg
+	}
+}
+
+// ServerCredentials loads the TLS material described by cfg and returns
+// transport credentials that pick up changes to the files.
+func ServerCredentials(cfg TLSConfig, logger *zap.Logger) (credentials.TransportCredentials, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, fmt.Errorf("TLS requires both a certificate and a key file")
+	}
+	if cfg.RequireClientCert && cfg.ClientCAFile == "" {
+		return nil, fmt.Errorf("requiring client certificates needs a client CA file")
+	}
+
+	r, err := newCertReloader(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(&tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		GetConfigForClient: r.configForClient,
+	}), nil
+}
+
+// certReloader serves the TLS configuration built from the files in cfg,
+// rebuilding it when one of them changes.
+type certReloader struct {
+	cfg    TLSConfig
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	current *tls.Config
+	stamps  []fileStamp
+	checked time.Time
+}
+
+// fileStamp identifies a version of a file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// newCertReloader loads the files in cfg.
+func newCertReloader(cfg TLSConfig, logger *zap.Logger) (*certReloader, error) {
+	r := &certReloader{cfg: cfg, logger: logger}
+	current, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	r.current = current
+	r.stamps = r.stat()
+	r.checked = time.Now()
+	return r, nil
+}
+
+func (r *certReloader) configForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checked) < certCheckInterval {
+		return r.current, nil
+	}
+	r.checked = time.Now()
+
+	stamps := r.stat()
+	if equalStamps(stamps, r.stamps) {
+		return r.current, nil
+	}
+	// A rotation may still be in progress, e.g. the certificate has been
+	// replaced but not yet the key; keep the old configuration and retry
+	// on a later handshake.
+	next, err := r.load()
+	if err != nil {
+		r.logger.Error("failed to reload TLS certificates, keeping the current ones", zap.Error(err))
+		return r.current, nil
+	}
+	r.logger.Info("reloaded TLS certificates", zap.String("cert", r.cfg.CertFile))
+	r.current = next
+	r.stamps = stamps
+	return r.current, nil
+}
+
+func (r *certReloader) load() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load server certificate: %w", err)
+	}
+	cfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("load client CA: no certificates found in %s", r.cfg.ClientCAFile)
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+		if r.cfg.RequireClientCert {
+			cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	return cfg, nil
+}
+
+// stat stamps every configured file. Files that cannot be read get a zero
+// stamp, which differs from any readable version.
+func (r *certReloader) stat() []fileStamp {
+	paths := []string{r.cfg.CertFile, r.cfg.KeyFile, r.cfg.ClientCAFile}
+	stamps := make([]fileStamp, len(paths))
+	for i, path := range paths {
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil {
+			stamps[i] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return stamps
+}
+
+func equalStamps(a, b []fileStamp) bool {
+	for i := range a {
+		if !a[i].modTime.Equal(b[i].modTime) || a[i].size != b[i].size {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
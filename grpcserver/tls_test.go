@@ -0,0 +1,137 @@
+package grpcserver
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// writeCert writes a self-signed certificate for name and its key to
+// certFile and keyFile, stamped with modTime.
+func writeCert(t *testing.T, certFile, keyFile, name string, modTime time.Time) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeStamped(t, certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), modTime)
+	writeStamped(t, keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), modTime)
+}
+
+// writeStamped writes a file with the given modification time, so that a
+// rewrite is noticed however coarse the file system's clock is.
+func writeStamped(t *testing.T, path string, data []byte, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// servedName handshakes with the reloader's configuration, as a new client
+// connection would once the check interval has passed, and returns the
+// common name of the certificate served.
+func servedName(t *testing.T, r *certReloader) string {
+	t.Helper()
+	r.mu.Lock()
+	r.checked = time.Time{}
+	r.mu.Unlock()
+
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+	server := tls.Server(serverConn, &tls.Config{GetConfigForClient: r.configForClient})
+	go server.Handshake()
+
+	client := tls.Client(clientConn, &tls.Config{InsecureSkipVerify: true})
+	if err := client.Handshake(); err != nil {
+		t.Fatal(err)
+	}
+	return client.ConnectionState().PeerCertificates[0].Subject.CommonName
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	cfg := TLSConfig{CertFile: filepath.Join(dir, "server.crt"), KeyFile: filepath.Join(dir, "server.key")}
+	stamp := time.Now().Add(-time.Hour)
+	writeCert(t, cfg.CertFile, cfg.KeyFile, "first", stamp)
+
+	r, err := newCertReloader(cfg, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := servedName(t, r); got != "first" {
+		t.Fatalf("served %q, want the first certificate", got)
+	}
+
+	stamp = stamp.Add(time.Minute)
+	writeCert(t, cfg.CertFile, cfg.KeyFile, "second", stamp)
+	if got := servedName(t, r); got != "second" {
+		t.Errorf("served %q after rotation, want the second certificate", got)
+	}
+
+	// A half-written rotation keeps the current certificate
+	stamp = stamp.Add(time.Minute)
+	writeStamped(t, cfg.CertFile, []byte("not a certificate"), stamp)
+	if got := servedName(t, r); got != "second" {
+		t.Errorf("served %q with an invalid certificate file, want the second certificate", got)
+	}
+
+	// and is picked up once it completes
+	stamp = stamp.Add(time.Minute)
+	writeCert(t, cfg.CertFile, cfg.KeyFile, "third", stamp)
+	if got := servedName(t, r); got != "third" {
+		t.Errorf("served %q after the rotation completed, want the third certificate", got)
+	}
+}
+
+func TestCertReloaderChecksAtMostEveryInterval(t *testing.T) {
+	dir := t.TempDir()
+	cfg := TLSConfig{CertFile: filepath.Join(dir, "server.crt"), KeyFile: filepath.Join(dir, "server.key")}
+	writeCert(t, cfg.CertFile, cfg.KeyFile, "first", time.Now().Add(-time.Hour))
+	r, err := newCertReloader(cfg, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeCert(t, cfg.CertFile, cfg.KeyFile, "second", time.Now())
+	current, err := r.configForClient(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(current.Certificates[0].Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leaf.Subject.CommonName != "first" {
+		t.Errorf("served %q within %v of the last check, want the first certificate", leaf.Subject.CommonName, certCheckInterval)
+	}
+}
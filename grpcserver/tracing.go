@@ -0,0 +1,21 @@
+package grpcserver
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer creates the server's spans through the global tracer provider. The
+// RPC spans themselves come from the otelgrpc stats handler, which also
+// extracts the caller's W3C trace context from the request metadata.
+var tracer = otel.Tracer("github.com/TFMV/ArrowLink/grpcserver")
+
+// endSpan marks span as failed if err is set, then ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
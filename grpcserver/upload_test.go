@@ -0,0 +1,143 @@
+package grpcserver
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/TFMV/ArrowLink/arrow"
+	pb "github.com/TFMV/ArrowLink/proto/dataexchange"
+	arrowgo "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var uploadSchema = arrowgo.NewSchema([]arrowgo.Field{
+	{Name: "id", Type: arrowgo.PrimitiveTypes.Int64},
+	{Name: "label", Type: arrowgo.BinaryTypes.String, Nullable: true},
+}, nil)
+
+// uploadPayload serializes one record batch per entry of batches, numbering
+// the rows from first.
+func uploadPayload(t *testing.T, schema *arrowgo.Schema, first int64, batches ...int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := ipc.NewWriter(&buf, ipc.WithSchema(schema))
+	builder := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer builder.Release()
+	id := first
+	for _, rows := range batches {
+		for i := 0; i < rows; i++ {
+			builder.Field(0).(*array.Int64Builder).Append(id)
+			if schema.NumFields() > 1 {
+				builder.Field(1).(*array.StringBuilder).Append("row")
+			}
+			id++
+		}
+		rec := builder.NewRecord()
+		err := w.Write(rec)
+		rec.Release()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// upload sends payloads to SendArrowData and returns the server's Ack.
+func upload(t *testing.T, sink arrow.ArrowSink, payloads ...[]byte) (*pb.Ack, error) {
+	t.Helper()
+	conn := startTestServer(t, testCatalog(t, nil), WithSink(sink))
+	stream, err := pb.NewArrowDataServiceClient(conn).SendArrowData(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, payload := range payloads {
+		// A failed send means the server ended the stream; CloseAndRecv
+		// reports why
+		if err := stream.Send(&pb.ArrowData{Payload: payload}); err != nil {
+			break
+		}
+	}
+	return stream.CloseAndRecv()
+}
+
+func TestSendArrowData(t *testing.T) {
+	sink := arrow.NewMemorySink(uploadSchema)
+	defer sink.Release()
+	first := uploadPayload(t, uploadSchema, 0, 3, 2)
+	corrupt := []byte("not an arrow stream")
+	second := uploadPayload(t, uploadSchema, 5, 4)
+
+	ack, err := upload(t, sink, first, corrupt, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ack.Rows != 9 || ack.Batches != 3 || ack.Bytes != int64(len(first)+len(second)) {
+		t.Errorf("ack = %d rows, %d batches, %d bytes; want 9, 3, %d", ack.Rows, ack.Batches, ack.Bytes, len(first)+len(second))
+	}
+	if ack.RejectedPayloads != 1 || len(ack.Errors) != 1 {
+		t.Errorf("ack rejected %d payloads with errors %q, want the corrupt one", ack.RejectedPayloads, ack.Errors)
+	}
+
+	records := sink.Records()
+	if len(records) != 3 {
+		t.Fatalf("sink has %d batches, want 3", len(records))
+	}
+	var want int64
+	for i, rec := range records {
+		if !rec.Schema().Equal(uploadSchema) {
+			t.Errorf("batch %d has schema %s", i, rec.Schema())
+		}
+		ids := rec.Column(0).(*array.Int64)
+		for j := 0; j < ids.Len(); j++ {
+			if ids.Value(j) != want {
+				t.Fatalf("batch %d row %d has id %d, want %d", i, j, ids.Value(j), want)
+			}
+			want++
+		}
+	}
+	if want != 9 {
+		t.Errorf("sink has %d rows, want 9", want)
+	}
+}
+
+func TestSendArrowDataAnySchema(t *testing.T) {
+	schema := arrowgo.NewSchema([]arrowgo.Field{{Name: "n", Type: arrowgo.PrimitiveTypes.Int64}}, nil)
+	ack, err := upload(t, arrow.NewDiscardSink(nil), uploadPayload(t, schema, 0, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ack.Rows != 10 || ack.Batches != 1 || ack.RejectedPayloads != 0 {
+		t.Errorf("ack = %v", ack)
+	}
+}
+
+func TestSendArrowDataSchemaMismatch(t *testing.T) {
+	for name, schema := range map[string]*arrowgo.Schema{
+		"missing field": arrowgo.NewSchema(uploadSchema.Fields()[:1], nil),
+		"renamed field": arrowgo.NewSchema([]arrowgo.Field{
+			{Name: "id", Type: arrowgo.PrimitiveTypes.Int64},
+			{Name: "name", Type: arrowgo.BinaryTypes.String},
+		}, nil),
+	} {
+		t.Run(name, func(t *testing.T) {
+			sink := arrow.NewMemorySink(uploadSchema)
+			defer sink.Release()
+			_, err := upload(t, sink, uploadPayload(t, uploadSchema, 0, 2), uploadPayload(t, schema, 2, 2))
+			if status.Code(err) != codes.InvalidArgument {
+				t.Fatalf("upload error = %v, want InvalidArgument", err)
+			}
+			// Batches accepted before the mismatch stay in the sink
+			if n := len(sink.Records()); n != 1 {
+				t.Errorf("sink has %d batches, want the 1 before the mismatch", n)
+			}
+		})
+	}
+}
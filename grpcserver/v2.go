@@ -0,0 +1,370 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/TFMV/ArrowLink/arrow"
+	"github.com/TFMV/ArrowLink/metrics"
+	pb "github.com/TFMV/ArrowLink/proto/dataexchange"
+	pbv2 "github.com/TFMV/ArrowLink/proto/dataexchange/v2"
+	arrowgo "github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// progressInterval is the minimum time between two Progress messages.
+const progressInterval = time.Second
+
+// v2Server implements version 2 of the dataexchange protocol on top of a
+// Server, sharing its catalog, compression, authorization and metrics.
+type v2Server struct {
+	pbv2.UnimplementedArrowDataServiceServer
+	srv *Server
+}
+
+// NewV2Server creates a dataexchange.v2 service backed by the same catalog
+// and settings as s. Register it with pbv2.RegisterArrowDataServiceServer.
+func NewV2Server(s *Server) pbv2.ArrowDataServiceServer {
+	return &v2Server{srv: s}
+}
+
+// GetArrowData streams the requested dataset as a Schema message, then the
+// IPC messages of each record batch, dictionaries first. A Progress message
+// follows a batch at most once per progressInterval, and a Summary ends the
+// stream. Batches are encoded one ahead of sending, as in version 1.
+func (v *v2Server) GetArrowData(req *pbv2.DataRequest, stream pbv2.ArrowDataService_GetArrowDataServer) error {
+	st, err := v.open(stream.Context(), req, stream, 0)
+	if err != nil {
+		return err
+	}
+	defer st.close()
+
+	encoded, stop := st.encode(nil)
+	defer stop()
+	for b := range encoded {
+		if err := st.sendBatch(b); err != nil {
+			return err
+		}
+	}
+	// The encoder stops without an error once the stream is done
+	if err := st.ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return st.sendSummary()
+}
+
+// PullArrowData streams a dataset like GetArrowData, sending each record
+// batch only once the client's credit allows it. The dataset is read by a
+// single worker, and the next batch is only read once the previous one was
+// sent, so the server holds no more than a couple of batches beyond the
+// client's window. That next batch is read before there is credit for it, so
+// that the end of the dataset is noticed and the Summary sent without
+// waiting for more credit.
+func (v *v2Server) PullArrowData(stream pbv2.ArrowDataService_PullArrowDataServer) error {
+	ctx := stream.Context()
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	req := first.GetRequest()
+	if req == nil {
+		return status.Error(codes.InvalidArgument, "the first message must be a data request")
+	}
+
+	grants := make(chan grant)
+	go receiveCredit(ctx, stream, grants)
+
+	st, err := v.open(ctx, req, stream, 1)
+	if err != nil {
+		return err
+	}
+	defer st.close()
+
+	demand := make(chan struct{}, 1)
+	encoded, stop := st.encode(demand)
+	defer stop()
+
+	var c credit
+	for {
+		demand <- struct{}{}
+		b, ok := <-encoded
+		if !ok {
+			break
+		}
+		if b.err != nil {
+			return b.err
+		}
+		for !c.allows() {
+			select {
+			case g := <-grants:
+				if err := c.add(g); err != nil {
+					return err
+				}
+			case <-ctx.Done():
+				return status.FromContextError(ctx.Err()).Err()
+			}
+		}
+		if err := st.sendBatch(b); err != nil {
+			return err
+		}
+		c.spend(b.bytes)
+	}
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return st.sendSummary()
+}
+
+// grant is a message received from a PullArrowData client, or the error
+// that ended its side of the stream.
+type grant struct {
+	credit *pbv2.Credit
+	err    error
+}
+
+// receiveCredit delivers the credit sent by the client until its side of the
+// stream ends or ctx is done.
+func receiveCredit(ctx context.Context, stream pbv2.ArrowDataService_PullArrowDataServer, grants chan<- grant) {
+	for {
+		msg, err := stream.Recv()
+		g := grant{err: err}
+		if err == nil {
+			if g.credit = msg.GetCredit(); g.credit == nil {
+				g.err = status.Error(codes.InvalidArgument, "only credit may follow the data request")
+			}
+		}
+		select {
+		case grants <- g:
+		case <-ctx.Done():
+			return
+		}
+		if g.err != nil {
+			return
+		}
+	}
+}
+
+// credit tracks what a PullArrowData client allows the server to send.
+type credit struct {
+	batches, bytes           int64
+	limitBatches, limitBytes bool
+}
+
+func (c *credit) add(g grant) error {
+	if errors.Is(g.err, io.EOF) {
+		return status.Error(codes.FailedPrecondition, "the client closed the stream with no credit left")
+	}
+	if g.err != nil {
+		return g.err
+	}
+	if g.credit.GetBatches() < 0 || g.credit.GetBytes() < 0 {
+		return status.Error(codes.InvalidArgument, "credit must not be negative")
+	}
+	if g.credit.GetBatches() > 0 {
+		c.batches += g.credit.GetBatches()
+		c.limitBatches = true
+	}
+	if g.credit.GetBytes() > 0 {
+		c.bytes += g.credit.GetBytes()
+		c.limitBytes = true
+	}
+	return nil
+}
+
+// allows reports whether the next batch may be sent. Nothing is sent before
+// the first credit.
+func (c *credit) allows() bool {
+	if !c.limitBatches && !c.limitBytes {
+		return false
+	}
+	return (!c.limitBatches || c.batches > 0) && (!c.limitBytes || c.bytes > 0)
+}
+
+func (c *credit) spend(bytes int) {
+	c.batches--
+	c.bytes -= int64(bytes)
+}
+
+// v2Stream is a version 2 stream in progress, from its Schema message to its
+// Summary.
+type v2Stream struct {
+	srv      *Server
+	ctx      context.Context
+	stream   grpc.ServerStream
+	ds       arrow.Dataset
+	codec    arrow.Codec
+	reader   array.RecordReader
+	position *resumePosition
+	encoder  *arrow.MessageEncoder
+	streamed *metrics.Stream
+	stats    streamStats
+
+	// index is the position of the next record batch, counting those sent
+	// by the stream this one resumes.
+	index        int64
+	lastProgress time.Time
+}
+
+// open resolves and reads the requested dataset, and sends the Schema
+// message. parallelism bounds the goroutines building batches; zero leaves
+// it to the dataset. The caller must close the stream.
+func (v *v2Server) open(ctx context.Context, req *pbv2.DataRequest, stream grpc.ServerStream, parallelism int) (*v2Stream, error) {
+	s := v.srv
+	if req.GetLimit() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "negative limit %d", req.GetLimit())
+	}
+	if req.GetBatchSize() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "negative batch size %d", req.GetBatchSize())
+	}
+	ds, err := s.dataset(ctx, req.GetDataset())
+	if err != nil {
+		return nil, err
+	}
+	// The two versions number their codecs alike.
+	codec, err := s.negotiateCompression(ctx, pb.Compression(req.GetCompression()), req.GetCompressionLevel())
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := s.batchSize
+	if req.GetBatchSize() > 0 {
+		batchSize = int(req.GetBatchSize())
+	}
+	opts := arrow.ReadOptions{BatchSize: batchSize, Allocator: s.mem, Parallelism: parallelism}
+	query := arrow.Query{Columns: req.GetColumns(), Filter: req.GetFilter(), Limit: req.GetLimit()}
+	queried, position, err := s.readResumable(ctx, ds, opts, query, req.GetResumeToken())
+	if err != nil {
+		return nil, err
+	}
+
+	method, _ := grpc.Method(ctx)
+	st := &v2Stream{
+		srv:          s,
+		ctx:          ctx,
+		stream:       stream,
+		ds:           ds,
+		codec:        codec,
+		reader:       queried,
+		position:     position,
+		index:        position.sent(),
+		encoder:      arrow.NewMessageEncoder(queried.Schema(), codec, s.mem),
+		streamed:     s.metrics.Stream(method, ds.Name, metrics.Sent),
+		lastProgress: time.Now(),
+	}
+	if err := reportCompression(stream, codec); err != nil {
+		st.close()
+		return nil, err
+	}
+	schema := st.encoder.Schema()
+	st.stats.Bytes += int64(len(schema))
+	err = st.send(&pbv2.DataResponse{Message: &pbv2.DataResponse_Schema{Schema: &pbv2.Schema{
+		Schema:        schema,
+		Compression:   compressionV2(codec),
+		EstimatedRows: ds.EstimatedRows(),
+	}}}, len(schema))
+	if err != nil {
+		st.close()
+		return nil, err
+	}
+	return st, nil
+}
+
+func (st *v2Stream) close() {
+	st.streamed.Done()
+	st.encoder.Close()
+	st.reader.Release()
+}
+
+func (st *v2Stream) send(resp *pbv2.DataResponse, size int) error {
+	_, span := tracer.Start(st.ctx, "Send", trace.WithAttributes(attribute.Int("arrow.bytes", size)))
+	err := st.stream.SendMsg(resp)
+	endSpan(span, err)
+	return err
+}
+
+// encode starts encoding the stream's batches; see encodeAhead.
+func (st *v2Stream) encode(demand <-chan struct{}) (<-chan encodedBatch, func()) {
+	serialize := func(record arrowgo.Record) (encodedBatch, arrow.SerializeTimings, error) {
+		messages, timings, err := st.encoder.Encode(record)
+		b := encodedBatch{messages: messages, resumeToken: st.position.advance(record.NumRows())}
+		for _, m := range messages {
+			b.bytes += len(m.Data)
+		}
+		return b, timings, err
+	}
+	return st.srv.encodeAhead(st.ctx, st.ds.Name, st.reader, st.codec, serialize, false, demand)
+}
+
+// sendBatch sends the messages of an encoded batch, followed by a Progress
+// message if the last one is older than progressInterval.
+func (st *v2Stream) sendBatch(b encodedBatch) error {
+	if b.err != nil {
+		return b.err
+	}
+	for _, m := range b.messages {
+		resp := &pbv2.DataResponse{}
+		if m.Type == ipc.MessageDictionaryBatch {
+			resp.Message = &pbv2.DataResponse_DictionaryBatch{DictionaryBatch: &pbv2.DictionaryBatch{Data: m.Data}}
+		} else {
+			resp.Message = &pbv2.DataResponse_RecordBatch{RecordBatch: &pbv2.RecordBatch{
+				Data:        m.Data,
+				Rows:        b.rows,
+				Index:       st.index,
+				ResumeToken: b.resumeToken,
+			}}
+		}
+		if err := st.send(resp, len(m.Data)); err != nil {
+			return err
+		}
+	}
+	st.stats.add(b)
+	st.streamed.Add(1, b.rows, b.bytes)
+	st.index++
+
+	if time.Since(st.lastProgress) < progressInterval {
+		return nil
+	}
+	st.lastProgress = time.Now()
+	return st.send(&pbv2.DataResponse{Message: &pbv2.DataResponse_Progress{Progress: &pbv2.Progress{
+		Rows:          st.stats.Rows,
+		Batches:       st.stats.Batches,
+		Bytes:         st.stats.Bytes,
+		EstimatedRows: st.ds.EstimatedRows(),
+	}}}, 0)
+}
+
+func (st *v2Stream) sendSummary() error {
+	return st.send(&pbv2.DataResponse{Message: &pbv2.DataResponse_Summary{Summary: &pbv2.Summary{
+		GenerationMs:    milliseconds(st.stats.Generation),
+		SerializationMs: milliseconds(st.stats.Serialization),
+		CompressionMs:   milliseconds(st.stats.Compression),
+		Rows:            st.stats.Rows,
+		Batches:         st.stats.Batches,
+		Bytes:           st.stats.Bytes,
+	}}}, 0)
+}
+
+func compressionV2(codec arrow.Codec) pbv2.Compression {
+	switch codec {
+	case arrow.CodecNone:
+		return pbv2.Compression_COMPRESSION_NONE
+	case arrow.CodecLZ4Frame:
+		return pbv2.Compression_COMPRESSION_LZ4_FRAME
+	case arrow.CodecZstd:
+		return pbv2.Compression_COMPRESSION_ZSTD
+	default:
+		return pbv2.Compression_COMPRESSION_UNSPECIFIED
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,274 @@
+package grpcserver
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/TFMV/ArrowLink/arrow"
+	pbv2 "github.com/TFMV/ArrowLink/proto/dataexchange/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// quiet is how long a pull stream must stay silent to count as waiting for
+// credit.
+const quiet = 200 * time.Millisecond
+
+type received struct {
+	resp *pbv2.DataResponse
+	err  error
+}
+
+// pullStream is a PullArrowData call whose responses are received in the
+// background, so that a test can tell a waiting server from a slow one.
+type pullStream struct {
+	t      *testing.T
+	stream pbv2.ArrowDataService_PullArrowDataClient
+	recv   chan received
+}
+
+// startPull serves a 500-row demo dataset and opens a pull stream for it in
+// batches of 100 rows. The Schema message has been received on return.
+func startPull(t *testing.T) *pullStream {
+	t.Helper()
+	cat := testCatalog(t, map[string]arrow.ArrowService{"demo": arrow.NewDemoArrowService(500)})
+	client := pbv2.NewArrowDataServiceClient(startTestServer(t, cat))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	stream, err := client.PullArrowData(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &pullStream{t: t, stream: stream, recv: make(chan received, 16)}
+	go func() {
+		for {
+			resp, err := stream.Recv()
+			p.recv <- received{resp, err}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	p.send(&pbv2.PullRequest{Message: &pbv2.PullRequest_Request{Request: &pbv2.DataRequest{Dataset: "demo", BatchSize: 100}}})
+	if r := p.next(); r.GetSchema() == nil {
+		t.Fatalf("first response is %v, want the schema", r)
+	}
+	return p
+}
+
+func (p *pullStream) send(req *pbv2.PullRequest) {
+	p.t.Helper()
+	if err := p.stream.Send(req); err != nil {
+		p.t.Fatal(err)
+	}
+}
+
+func (p *pullStream) grant(batches, bytes int64) {
+	p.t.Helper()
+	p.send(&pbv2.PullRequest{Message: &pbv2.PullRequest_Credit{Credit: &pbv2.Credit{Batches: batches, Bytes: bytes}}})
+}
+
+// poll returns the next response other than Progress, or nil if none
+// arrives within wait.
+func (p *pullStream) poll(wait time.Duration) (*pbv2.DataResponse, error, bool) {
+	timeout := time.After(wait)
+	for {
+		select {
+		case r := <-p.recv:
+			if r.err == nil && r.resp.GetProgress() != nil {
+				continue
+			}
+			return r.resp, r.err, true
+		case <-timeout:
+			return nil, nil, false
+		}
+	}
+}
+
+// next returns the next response other than Progress, failing the test on
+// an error.
+func (p *pullStream) next() *pbv2.DataResponse {
+	p.t.Helper()
+	resp, err, ok := p.poll(5 * time.Second)
+	if !ok {
+		p.t.Fatal("no response")
+	}
+	if err != nil {
+		p.t.Fatalf("stream failed: %v", err)
+	}
+	return resp
+}
+
+// nextBatch skips dictionaries and returns the next record batch.
+func (p *pullStream) nextBatch() *pbv2.RecordBatch {
+	p.t.Helper()
+	b, _ := p.nextBatchSize()
+	return b
+}
+
+// nextBatchSize returns the next record batch and the bytes it is charged,
+// which include the dictionaries sent before it.
+func (p *pullStream) nextBatchSize() (*pbv2.RecordBatch, int64) {
+	p.t.Helper()
+	var size int64
+	for {
+		resp := p.next()
+		if d := resp.GetDictionaryBatch(); d != nil {
+			size += int64(len(d.Data))
+			continue
+		}
+		if resp.GetRecordBatch() == nil {
+			p.t.Fatalf("got %v, want a record batch", resp)
+		}
+		return resp.GetRecordBatch(), size + int64(len(resp.GetRecordBatch().Data))
+	}
+}
+
+// expectWaiting checks that nothing arrives for a while.
+func (p *pullStream) expectWaiting() {
+	p.t.Helper()
+	if resp, err, ok := p.poll(quiet); ok {
+		p.t.Fatalf("got %v (error %v) without credit", resp, err)
+	}
+}
+
+// expectError waits for the stream to fail with code.
+func (p *pullStream) expectError(code codes.Code) {
+	p.t.Helper()
+	for {
+		_, err, ok := p.poll(5 * time.Second)
+		if !ok {
+			p.t.Fatalf("stream did not fail, want %s", code)
+		}
+		if err != nil {
+			if got := status.Code(err); got != code {
+				p.t.Fatalf("stream failed with %s, want %s (%v)", got, code, err)
+			}
+			return
+		}
+	}
+}
+
+func TestPullNothingBeforeCredit(t *testing.T) {
+	p := startPull(t)
+	p.expectWaiting()
+	p.grant(1, 0)
+	if b := p.nextBatch(); b.Index != 0 || b.Rows != 100 {
+		t.Errorf("first batch has index %d and %d rows", b.Index, b.Rows)
+	}
+}
+
+func TestPullBatchCredit(t *testing.T) {
+	p := startPull(t)
+	p.grant(2, 0)
+	p.nextBatch()
+	p.nextBatch()
+	p.expectWaiting()
+	p.grant(1, 0)
+	if b := p.nextBatch(); b.Index != 2 {
+		t.Errorf("batch index %d, want 2", b.Index)
+	}
+	p.expectWaiting()
+}
+
+func TestPullByteCredit(t *testing.T) {
+	p := startPull(t)
+	// Any positive byte credit lets one batch through, overdrawing it
+	p.grant(0, 1)
+	_, size := p.nextBatchSize()
+	p.expectWaiting()
+
+	// The overdraw must be paid back before the next batch
+	p.grant(0, size-1)
+	p.expectWaiting()
+	p.grant(0, 1)
+	if b := p.nextBatch(); b.Index != 1 {
+		t.Errorf("batch index %d, want 1", b.Index)
+	}
+}
+
+func TestPullBatchAndByteCredit(t *testing.T) {
+	p := startPull(t)
+	// Both kinds of credit must allow a batch once both were granted
+	p.grant(1, 1<<20)
+	p.nextBatch()
+	p.expectWaiting()
+	p.grant(0, 1<<20)
+	p.expectWaiting()
+	p.grant(1, 0)
+	p.nextBatch()
+}
+
+func TestPullSummaryWithoutExtraCredit(t *testing.T) {
+	p := startPull(t)
+	p.grant(5, 0)
+	for i := int64(0); i < 5; i++ {
+		if b := p.nextBatch(); b.Index != i {
+			t.Fatalf("batch index %d, want %d", b.Index, i)
+		}
+	}
+	summary := p.next().GetSummary()
+	if summary == nil {
+		t.Fatal("no summary after the last batch")
+	}
+	if summary.Rows != 500 || summary.Batches != 5 {
+		t.Errorf("summary counts %d rows in %d batches", summary.Rows, summary.Batches)
+	}
+	if _, err, _ := p.poll(5 * time.Second); err != io.EOF {
+		t.Errorf("stream ended with %v, want EOF", err)
+	}
+}
+
+func TestPullNegativeCredit(t *testing.T) {
+	for name, credit := range map[string][2]int64{
+		"batches": {-1, 0},
+		"bytes":   {0, -1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := startPull(t)
+			p.grant(credit[0], credit[1])
+			p.expectError(codes.InvalidArgument)
+		})
+	}
+}
+
+func TestPullHalfCloseWithoutCredit(t *testing.T) {
+	p := startPull(t)
+	p.grant(1, 0)
+	p.nextBatch()
+	if err := p.stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	p.expectError(codes.FailedPrecondition)
+}
+
+func TestPullHalfCloseWithCredit(t *testing.T) {
+	p := startPull(t)
+	p.grant(5, 0)
+	if err := p.stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		p.nextBatch()
+	}
+	if p.next().GetSummary() == nil {
+		t.Error("no summary after the last batch")
+	}
+}
+
+func TestPullFirstMessageMustBeRequest(t *testing.T) {
+	cat := testCatalog(t, map[string]arrow.ArrowService{"demo": arrow.NewDemoArrowService(10)})
+	client := pbv2.NewArrowDataServiceClient(startTestServer(t, cat))
+	stream, err := client.PullArrowData(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(&pbv2.PullRequest{Message: &pbv2.PullRequest_Credit{Credit: &pbv2.Credit{Batches: 1}}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("error %v, want InvalidArgument", err)
+	}
+}
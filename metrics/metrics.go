@@ -0,0 +1,262 @@
+// Package metrics exposes Prometheus metrics for the ArrowLink server and its
+// Arrow pipeline: RPC counts and latencies, data streamed, time spent
+// generating, serializing and compressing batches, active streams and the
+// bytes held by the Arrow allocator.
+//
+// Every method is safe to call on a nil *Metrics, which records nothing, so
+// instrumented code does not need to check whether metrics are enabled.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const namespace = "arrowlink"
+
+// Directions of streamed data.
+const (
+	Sent     = "sent"
+	Received = "received"
+)
+
+// Metrics holds the ArrowLink collectors.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests      *prometheus.CounterVec
+	latency       *prometheus.HistogramVec
+	activeStreams *prometheus.GaugeVec
+	bytes         *prometheus.CounterVec
+	rows          *prometheus.CounterVec
+	batches       *prometheus.HistogramVec
+	generation    *prometheus.HistogramVec
+	serialization *prometheus.HistogramVec
+	compression   *prometheus.HistogramVec
+
+	allocated atomic.Int64
+}
+
+// batchBuckets cover per-batch durations from 50µs to about 3s.
+var batchBuckets = prometheus.ExponentialBuckets(50e-6, 2, 17)
+
+// New creates the collectors and registers them, together with the Go
+// runtime and process collectors, in a new registry.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rpc_requests_total",
+			Help:      "RPCs handled, by method and status code.",
+		}, []string{"method", "code"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "rpc_duration_seconds",
+			Help:      "Time to handle an RPC, including streaming every batch.",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 2, 18),
+		}, []string{"method"}),
+		activeStreams: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "active_streams",
+			Help:      "Streaming RPCs in progress.",
+		}, []string{"method"}),
+		bytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "stream_bytes_total",
+			Help:      "Serialized Arrow bytes streamed, by method, dataset and direction.",
+		}, []string{"method", "dataset", "direction"}),
+		rows: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "stream_rows_total",
+			Help:      "Rows streamed, by method, dataset and direction.",
+		}, []string{"method", "dataset", "direction"}),
+		batches: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "stream_batches",
+			Help:      "Record batches per stream.",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 10),
+		}, []string{"method", "dataset", "direction"}),
+		generation: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "batch_generation_seconds",
+			Help:      "Time to produce a record batch, filtering and projection included.",
+			Buckets:   batchBuckets,
+		}, []string{"dataset"}),
+		serialization: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "batch_serialization_seconds",
+			Help:      "Time to serialize a record batch to IPC, compression included.",
+			Buckets:   batchBuckets,
+		}, []string{"codec"}),
+		compression: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "batch_compression_seconds",
+			Help:      "Time to encode and compress the buffers of a record batch.",
+			Buckets:   batchBuckets,
+		}, []string{"codec"}),
+	}
+	m.registry.MustRegister(
+		m.requests, m.latency, m.activeStreams,
+		m.bytes, m.rows, m.batches,
+		m.generation, m.serialization, m.compression,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "allocator_bytes",
+			Help:      "Bytes currently allocated by the Arrow allocators of the server.",
+		}, func() float64 { return float64(m.allocated.Load()) }),
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	if m == nil {
+		return http.NotFoundHandler()
+	}
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Registry returns the registry holding the collectors, for registering
+// application metrics alongside them.
+func (m *Metrics) Registry() *prometheus.Registry {
+	if m == nil {
+		return nil
+	}
+	return m.registry
+}
+
+// ObserveGeneration records the time taken to produce one batch of dataset.
+func (m *Metrics) ObserveGeneration(dataset string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.generation.WithLabelValues(dataset).Observe(d.Seconds())
+}
+
+// ObserveSerialization records the time taken to serialize one batch with
+// codec.
+func (m *Metrics) ObserveSerialization(codec string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.serialization.WithLabelValues(codec).Observe(d.Seconds())
+}
+
+// ObserveCompression records the time spent compressing one batch with codec.
+func (m *Metrics) ObserveCompression(codec string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.compression.WithLabelValues(codec).Observe(d.Seconds())
+}
+
+// Stream accumulates the data moved by one streaming RPC.
+type Stream struct {
+	m       *Metrics
+	labels  prometheus.Labels
+	batches int
+	done    bool
+}
+
+// Stream starts accounting for data moved in direction by a call of the full
+// gRPC method on dataset, which is empty for uploads. Call Done once the
+// stream ends.
+func (m *Metrics) Stream(method, dataset, direction string) *Stream {
+	if m == nil {
+		return nil
+	}
+	return &Stream{m: m, labels: prometheus.Labels{"method": method, "dataset": dataset, "direction": direction}}
+}
+
+// Add records batches holding rows in total, serialized into bytes.
+func (s *Stream) Add(batches int, rows int64, bytes int) {
+	if s == nil {
+		return
+	}
+	s.batches += batches
+	s.m.rows.With(s.labels).Add(float64(rows))
+	s.m.bytes.With(s.labels).Add(float64(bytes))
+}
+
+// Done records the number of batches in the stream. Later calls do nothing.
+func (s *Stream) Done() {
+	if s == nil || s.done {
+		return
+	}
+	s.done = true
+	s.m.batches.With(s.labels).Observe(float64(s.batches))
+}
+
+// UnaryServerInterceptor counts unary calls and measures their latency.
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.observeRPC(info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor counts streaming calls, measures their latency and
+// tracks how many are in progress.
+func (m *Metrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		active := m.activeStreams.WithLabelValues(info.FullMethod)
+		active.Inc()
+		defer active.Dec()
+
+		start := time.Now()
+		err := handler(srv, ss)
+		m.observeRPC(info.FullMethod, start, err)
+		return err
+	}
+}
+
+func (m *Metrics) observeRPC(method string, start time.Time, err error) {
+	m.requests.WithLabelValues(method, status.Code(err).String()).Inc()
+	m.latency.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
+
+// Allocator wraps mem so that the bytes it holds are included in the
+// allocator_bytes gauge.
+func (m *Metrics) Allocator(mem memory.Allocator) memory.Allocator {
+	if m == nil {
+		return mem
+	}
+	return &countingAllocator{Allocator: mem, allocated: &m.allocated}
+}
+
+type countingAllocator struct {
+	memory.Allocator
+	allocated *atomic.Int64
+}
+
+func (a *countingAllocator) Allocate(size int) []byte {
+	b := a.Allocator.Allocate(size)
+	a.allocated.Add(int64(len(b)))
+	return b
+}
+
+func (a *countingAllocator) Reallocate(size int, b []byte) []byte {
+	old := len(b)
+	b = a.Allocator.Reallocate(size, b)
+	a.allocated.Add(int64(len(b) - old))
+	return b
+}
+
+func (a *countingAllocator) Free(b []byte) {
+	a.allocated.Add(-int64(len(b)))
+	a.Allocator.Free(b)
+}
@@ -19,8 +19,10 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ArrowDataService_GetArrowData_FullMethodName  = "/dataexchange.ArrowDataService/GetArrowData"
-	ArrowDataService_SendArrowData_FullMethodName = "/dataexchange.ArrowDataService/SendArrowData"
+	ArrowDataService_GetArrowData_FullMethodName    = "/dataexchange.ArrowDataService/GetArrowData"
+	ArrowDataService_SendArrowData_FullMethodName   = "/dataexchange.ArrowDataService/SendArrowData"
+	ArrowDataService_ListDatasets_FullMethodName    = "/dataexchange.ArrowDataService/ListDatasets"
+	ArrowDataService_DescribeDataset_FullMethodName = "/dataexchange.ArrowDataService/DescribeDataset"
 )
 
 // ArrowDataServiceClient is the client API for ArrowDataService service.
@@ -28,9 +30,13 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ArrowDataServiceClient interface {
 	// Streaming response for efficient data transfer
-	GetArrowData(ctx context.Context, in *Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ArrowData], error)
+	GetArrowData(ctx context.Context, in *DataRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ArrowData], error)
 	// Accepts Arrow data and processes it
 	SendArrowData(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ArrowData, Ack], error)
+	// Lists the datasets registered with the server
+	ListDatasets(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*DatasetList, error)
+	// Describes a single dataset
+	DescribeDataset(ctx context.Context, in *DatasetRequest, opts ...grpc.CallOption) (*DatasetInfo, error)
 }
 
 type arrowDataServiceClient struct {
@@ -41,13 +47,13 @@ func NewArrowDataServiceClient(cc grpc.ClientConnInterface) ArrowDataServiceClie
 	return &arrowDataServiceClient{cc}
 }
 
-func (c *arrowDataServiceClient) GetArrowData(ctx context.Context, in *Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ArrowData], error) {
+func (c *arrowDataServiceClient) GetArrowData(ctx context.Context, in *DataRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ArrowData], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &ArrowDataService_ServiceDesc.Streams[0], ArrowDataService_GetArrowData_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[Empty, ArrowData]{ClientStream: stream}
+	x := &grpc.GenericClientStream[DataRequest, ArrowData]{ClientStream: stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -73,14 +79,38 @@ func (c *arrowDataServiceClient) SendArrowData(ctx context.Context, opts ...grpc
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type ArrowDataService_SendArrowDataClient = grpc.ClientStreamingClient[ArrowData, Ack]
 
+func (c *arrowDataServiceClient) ListDatasets(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*DatasetList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DatasetList)
+	err := c.cc.Invoke(ctx, ArrowDataService_ListDatasets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *arrowDataServiceClient) DescribeDataset(ctx context.Context, in *DatasetRequest, opts ...grpc.CallOption) (*DatasetInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DatasetInfo)
+	err := c.cc.Invoke(ctx, ArrowDataService_DescribeDataset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ArrowDataServiceServer is the server API for ArrowDataService service.
 // All implementations must embed UnimplementedArrowDataServiceServer
 // for forward compatibility.
 type ArrowDataServiceServer interface {
 	// Streaming response for efficient data transfer
-	GetArrowData(*Empty, grpc.ServerStreamingServer[ArrowData]) error
+	GetArrowData(*DataRequest, grpc.ServerStreamingServer[ArrowData]) error
 	// Accepts Arrow data and processes it
 	SendArrowData(grpc.ClientStreamingServer[ArrowData, Ack]) error
+	// Lists the datasets registered with the server
+	ListDatasets(context.Context, *Empty) (*DatasetList, error)
+	// Describes a single dataset
+	DescribeDataset(context.Context, *DatasetRequest) (*DatasetInfo, error)
 	mustEmbedUnimplementedArrowDataServiceServer()
 }
 
@@ -91,12 +121,18 @@ type ArrowDataServiceServer interface {
 // pointer dereference when methods are called.
 type UnimplementedArrowDataServiceServer struct{}
 
-func (UnimplementedArrowDataServiceServer) GetArrowData(*Empty, grpc.ServerStreamingServer[ArrowData]) error {
+func (UnimplementedArrowDataServiceServer) GetArrowData(*DataRequest, grpc.ServerStreamingServer[ArrowData]) error {
 	return status.Errorf(codes.Unimplemented, "method GetArrowData not implemented")
 }
 func (UnimplementedArrowDataServiceServer) SendArrowData(grpc.ClientStreamingServer[ArrowData, Ack]) error {
 	return status.Errorf(codes.Unimplemented, "method SendArrowData not implemented")
 }
+func (UnimplementedArrowDataServiceServer) ListDatasets(context.Context, *Empty) (*DatasetList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDatasets not implemented")
+}
+func (UnimplementedArrowDataServiceServer) DescribeDataset(context.Context, *DatasetRequest) (*DatasetInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DescribeDataset not implemented")
+}
 func (UnimplementedArrowDataServiceServer) mustEmbedUnimplementedArrowDataServiceServer() {}
 func (UnimplementedArrowDataServiceServer) testEmbeddedByValue()                          {}
 
@@ -119,11 +155,11 @@ func RegisterArrowDataServiceServer(s grpc.ServiceRegistrar, srv ArrowDataServic
 }
 
 func _ArrowDataService_GetArrowData_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(Empty)
+	m := new(DataRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(ArrowDataServiceServer).GetArrowData(m, &grpc.GenericServerStream[Empty, ArrowData]{ServerStream: stream})
+	return srv.(ArrowDataServiceServer).GetArrowData(m, &grpc.GenericServerStream[DataRequest, ArrowData]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
@@ -136,13 +172,58 @@ func _ArrowDataService_SendArrowData_Handler(srv interface{}, stream grpc.Server
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type ArrowDataService_SendArrowDataServer = grpc.ClientStreamingServer[ArrowData, Ack]
 
+func _ArrowDataService_ListDatasets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArrowDataServiceServer).ListDatasets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArrowDataService_ListDatasets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArrowDataServiceServer).ListDatasets(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArrowDataService_DescribeDataset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DatasetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArrowDataServiceServer).DescribeDataset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArrowDataService_DescribeDataset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArrowDataServiceServer).DescribeDataset(ctx, req.(*DatasetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ArrowDataService_ServiceDesc is the grpc.ServiceDesc for ArrowDataService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var ArrowDataService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "dataexchange.ArrowDataService",
 	HandlerType: (*ArrowDataServiceServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListDatasets",
+			Handler:    _ArrowDataService_ListDatasets_Handler,
+		},
+		{
+			MethodName: "DescribeDataset",
+			Handler:    _ArrowDataService_DescribeDataset_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "GetArrowData",
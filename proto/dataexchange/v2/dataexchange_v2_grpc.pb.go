@@ -0,0 +1,176 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.27.3
+// source: dataexchange_v2.proto
+
+package dataexchangev2
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ArrowDataService_GetArrowData_FullMethodName  = "/dataexchange.v2.ArrowDataService/GetArrowData"
+	ArrowDataService_PullArrowData_FullMethodName = "/dataexchange.v2.ArrowDataService/PullArrowData"
+)
+
+// ArrowDataServiceClient is the client API for ArrowDataService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Version 2 of the dataexchange protocol. Requests state their options
+// explicitly, and the Arrow IPC stream is split into typed messages instead
+// of opaque payloads.
+type ArrowDataServiceClient interface {
+	// Streams a dataset: a Schema first, then RecordBatch messages, each
+	// preceded by the DictionaryBatch messages it needs, with Progress along the
+	// way and a Summary last
+	GetArrowData(ctx context.Context, in *DataRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DataResponse], error)
+	// Streams a dataset under the client's flow control. The client sends a
+	// DataRequest and then grants Credit; the server responds as GetArrowData
+	// does, but sends a record batch only while the credit allows it
+	PullArrowData(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[PullRequest, DataResponse], error)
+}
+
+type arrowDataServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewArrowDataServiceClient(cc grpc.ClientConnInterface) ArrowDataServiceClient {
+	return &arrowDataServiceClient{cc}
+}
+
+func (c *arrowDataServiceClient) GetArrowData(ctx context.Context, in *DataRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DataResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ArrowDataService_ServiceDesc.Streams[0], ArrowDataService_GetArrowData_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DataRequest, DataResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ArrowDataService_GetArrowDataClient = grpc.ServerStreamingClient[DataResponse]
+
+func (c *arrowDataServiceClient) PullArrowData(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[PullRequest, DataResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ArrowDataService_ServiceDesc.Streams[1], ArrowDataService_PullArrowData_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PullRequest, DataResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ArrowDataService_PullArrowDataClient = grpc.BidiStreamingClient[PullRequest, DataResponse]
+
+// ArrowDataServiceServer is the server API for ArrowDataService service.
+// All implementations must embed UnimplementedArrowDataServiceServer
+// for forward compatibility.
+//
+// Version 2 of the dataexchange protocol. Requests state their options
+// explicitly, and the Arrow IPC stream is split into typed messages instead
+// of opaque payloads.
+type ArrowDataServiceServer interface {
+	// Streams a dataset: a Schema first, then RecordBatch messages, each
+	// preceded by the DictionaryBatch messages it needs, with Progress along the
+	// way and a Summary last
+	GetArrowData(*DataRequest, grpc.ServerStreamingServer[DataResponse]) error
+	// Streams a dataset under the client's flow control. The client sends a
+	// DataRequest and then grants Credit; the server responds as GetArrowData
+	// does, but sends a record batch only while the credit allows it
+	PullArrowData(grpc.BidiStreamingServer[PullRequest, DataResponse]) error
+	mustEmbedUnimplementedArrowDataServiceServer()
+}
+
+// UnimplementedArrowDataServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedArrowDataServiceServer struct{}
+
+func (UnimplementedArrowDataServiceServer) GetArrowData(*DataRequest, grpc.ServerStreamingServer[DataResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method GetArrowData not implemented")
+}
+func (UnimplementedArrowDataServiceServer) PullArrowData(grpc.BidiStreamingServer[PullRequest, DataResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method PullArrowData not implemented")
+}
+func (UnimplementedArrowDataServiceServer) mustEmbedUnimplementedArrowDataServiceServer() {}
+func (UnimplementedArrowDataServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeArrowDataServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ArrowDataServiceServer will
+// result in compilation errors.
+type UnsafeArrowDataServiceServer interface {
+	mustEmbedUnimplementedArrowDataServiceServer()
+}
+
+func RegisterArrowDataServiceServer(s grpc.ServiceRegistrar, srv ArrowDataServiceServer) {
+	// If the following call pancis, it indicates UnimplementedArrowDataServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ArrowDataService_ServiceDesc, srv)
+}
+
+func _ArrowDataService_GetArrowData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DataRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ArrowDataServiceServer).GetArrowData(m, &grpc.GenericServerStream[DataRequest, DataResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ArrowDataService_GetArrowDataServer = grpc.ServerStreamingServer[DataResponse]
+
+func _ArrowDataService_PullArrowData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ArrowDataServiceServer).PullArrowData(&grpc.GenericServerStream[PullRequest, DataResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ArrowDataService_PullArrowDataServer = grpc.BidiStreamingServer[PullRequest, DataResponse]
+
+// ArrowDataService_ServiceDesc is the grpc.ServiceDesc for ArrowDataService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ArrowDataService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dataexchange.v2.ArrowDataService",
+	HandlerType: (*ArrowDataServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetArrowData",
+			Handler:       _ArrowDataService_GetArrowData_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "PullArrowData",
+			Handler:       _ArrowDataService_PullArrowData_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "dataexchange_v2.proto",
+}
@@ -6,7 +6,7 @@ import pandas as pd
 import plotly.express as px
 import time
 from proto.dataexchange_pb2_grpc import ArrowDataServiceStub
-from proto.dataexchange_pb2 import Empty
+from proto.dataexchange_pb2 import DataRequest
 
 st.set_page_config(page_title="ArrowLink Dashboard", layout="wide")
 
@@ -33,11 +33,12 @@ def get_stub():
 def fetch_data():
     try:
         stub = get_stub()
-        response_stream = stub.GetArrowData(Empty())
+        response_stream = stub.GetArrowData(DataRequest())
+        batches = []
         for response in response_stream:
             reader = ipc.RecordBatchStreamReader(pa.BufferReader(response.payload))
-            table = reader.read_all()
-            return table.to_pandas()
+            batches.extend(reader)
+        return pa.Table.from_batches(batches).to_pandas()
     except Exception as e:
         st.error(f"Error fetching data: {e}")
         return None
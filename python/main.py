@@ -1,7 +1,10 @@
+import collections
 import grpc
+import io
 import pyarrow as pa
 import pyarrow.ipc as ipc
 import logging
+import queue
 import time
 import argparse
 import pandas as pd
@@ -10,7 +13,16 @@ import seaborn as sns
 from grpc import RpcError
 
 from proto.dataexchange_pb2_grpc import ArrowDataServiceStub
-from proto.dataexchange_pb2 import Empty
+from proto.dataexchange_pb2 import Compression, DataRequest, Empty
+from proto import dataexchange_v2_pb2 as v2
+from proto.dataexchange_v2_pb2_grpc import ArrowDataServiceStub as ArrowDataServiceV2Stub
+
+COMPRESSION_CODECS = {
+    "default": Compression.COMPRESSION_UNSPECIFIED,
+    "none": Compression.COMPRESSION_NONE,
+    "lz4": Compression.COMPRESSION_LZ4_FRAME,
+    "zstd": Compression.COMPRESSION_ZSTD,
+}
 
 
 class LoggingInterceptor(
@@ -40,14 +52,26 @@ class LoggingInterceptor(
         return continuation(client_call_details, request_iterator)
 
 
-def get_secure_channel(target, ca_cert_file, options):
+def get_secure_channel(target, ca_cert_file, options, client_cert_file=None, client_key_file=None):
     """
-    Creates a secure gRPC channel using the provided CA certificate.
+    Creates a secure gRPC channel using the provided CA certificate. When a
+    client certificate and key are given, they are presented to servers that
+    require mutual TLS.
     """
     try:
         with open(ca_cert_file, "rb") as f:
             trusted_certs = f.read()
-        credentials = grpc.ssl_channel_credentials(root_certificates=trusted_certs)
+        private_key = certificate_chain = None
+        if client_cert_file and client_key_file:
+            with open(client_key_file, "rb") as f:
+                private_key = f.read()
+            with open(client_cert_file, "rb") as f:
+                certificate_chain = f.read()
+        credentials = grpc.ssl_channel_credentials(
+            root_certificates=trusted_certs,
+            private_key=private_key,
+            certificate_chain=certificate_chain,
+        )
         return grpc.secure_channel(target, credentials, options=options)
     except FileNotFoundError:
         logging.warning(
@@ -74,6 +98,60 @@ def run():
     parser.add_argument(
         "--cert", type=str, default="certs/ca.crt", help="Path to CA certificate"
     )
+    parser.add_argument(
+        "--client-cert",
+        type=str,
+        default="",
+        help="Path to a client certificate, for servers that require mutual TLS",
+    )
+    parser.add_argument(
+        "--client-key", type=str, default="", help="Path to the client private key"
+    )
+    parser.add_argument(
+        "--dataset",
+        type=str,
+        default="",
+        help="Dataset to fetch (default: the server's default dataset)",
+    )
+    parser.add_argument(
+        "--list", action="store_true", help="List the server's datasets and exit"
+    )
+    parser.add_argument(
+        "--columns",
+        type=str,
+        default="",
+        help="Comma-separated columns to fetch (default: all)",
+    )
+    parser.add_argument(
+        "--filter",
+        type=str,
+        default="",
+        help="Server-side row filter, e.g. \"value > 50 AND category IN ('A', 'B')\"",
+    )
+    parser.add_argument(
+        "--compression",
+        choices=["default", "none", "lz4", "zstd"],
+        default="default",
+        help="IPC body compression to request (default: the server's choice)",
+    )
+    parser.add_argument(
+        "--compression-level",
+        type=int,
+        default=0,
+        help="Codec level to request (0: codec default, the only level the server accepts)",
+    )
+    parser.add_argument(
+        "--window",
+        type=int,
+        default=0,
+        help="Pull batches with flow control, keeping at most this many in flight (0: server push)",
+    )
+    parser.add_argument(
+        "--api-key", type=str, default="", help="API key for servers requiring authentication"
+    )
+    parser.add_argument(
+        "--token", type=str, default="", help="JWT bearer token for servers requiring authentication"
+    )
     args = parser.parse_args()
 
     logging.basicConfig(level=logging.INFO)
@@ -89,12 +167,26 @@ def run():
     ]
 
     # Create channel (secure if possible, otherwise insecure)
-    channel = get_secure_channel(target, ca_cert_file, options)
+    channel = get_secure_channel(
+        target, ca_cert_file, options, args.client_cert, args.client_key
+    )
 
     # Wrap the channel with a logging interceptor.
     intercepted_channel = grpc.intercept_channel(channel, LoggingInterceptor())
     stub = ArrowDataServiceStub(intercepted_channel)
 
+    # Credentials are sent as call metadata on every RPC.
+    metadata = []
+    if args.api_key:
+        metadata.append(("x-api-key", args.api_key))
+    if args.token:
+        metadata.append(("authorization", f"Bearer {args.token}"))
+
+    if args.list:
+        list_datasets(stub, metadata)
+        channel.close()
+        return
+
     max_retries = 3
     retry_delay = 5  # seconds
 
@@ -102,43 +194,66 @@ def run():
     if args.benchmark:
         start_time = time.time()
 
-    # Implement retry logic with a call deadline.
+    # Implement retry logic with a call deadline. Batches received before a
+    # failure are kept, and the next attempt resumes the stream after them.
+    progress = Progress()
     for attempt in range(1, max_retries + 1):
         try:
-            logging.info("Calling GetArrowData (attempt %d)...", attempt)
-            # Set a deadline of 30 seconds for the RPC call.
-            response_stream = stub.GetArrowData(Empty(), timeout=30)
-            for response in response_stream:
-                try:
-                    reader = ipc.RecordBatchStreamReader(
-                        pa.BufferReader(response.payload)
+            if progress.resume_token:
+                logging.info(
+                    "Resuming after %d batches (attempt %d)...",
+                    len(progress.batches), attempt,
+                )
+            else:
+                logging.info("Fetching data (attempt %d)...", attempt)
+            if args.window > 0:
+                pull_batches(intercepted_channel, args, progress, metadata)
+            else:
+                get_batches(stub, args, progress, metadata)
+            batches = progress.batches
+
+            try:
+                table = pa.Table.from_batches(batches)
+                df = table.to_pandas()
+
+                if args.benchmark:
+                    end_time = time.time()
+                    logging.info(
+                        f"Received {len(df)} rows in {len(batches)} batches in {end_time - start_time:.4f} seconds"
                     )
-                    table = reader.read_all()
-                    df = table.to_pandas()
-
-                    if args.benchmark:
-                        end_time = time.time()
-                        logging.info(
-                            f"Received {len(df)} rows in {end_time - start_time:.4f} seconds"
-                        )
-                        logging.info(
-                            f"Throughput: {len(df) / (end_time - start_time):.2f} rows/second"
-                        )
-                    else:
-                        logging.info(
-                            f"Received Arrow table with {len(df)} rows and {len(df.columns)} columns"
-                        )
-                        logging.info(f"Schema: {table.schema}")
-                        logging.info(f"Sample data:\n{df.head()}")
-
-                    if args.visualize:
-                        visualize_data(df)
-
-                except Exception as data_err:
-                    logging.error("Error processing Arrow data.", exc_info=data_err)
+                    logging.info(
+                        f"Throughput: {len(df) / (end_time - start_time):.2f} rows/second"
+                    )
+                else:
+                    logging.info(
+                        f"Received Arrow table with {len(df)} rows and {len(df.columns)} columns in {len(batches)} batches"
+                    )
+                    logging.info(f"Schema: {table.schema}")
+                    logging.info(f"Sample data:\n{df.head()}")
+
+                if args.visualize:
+                    visualize_data(df)
+
+            except Exception as data_err:
+                logging.error("Error processing Arrow data.", exc_info=data_err)
             break
         except RpcError as rpc_err:
             logging.error("gRPC error on attempt %d: %s", attempt, rpc_err)
+            if rpc_err.code() in (
+                grpc.StatusCode.UNAUTHENTICATED,
+                grpc.StatusCode.PERMISSION_DENIED,
+            ):
+                logging.error("Check the --api-key or --token credentials.")
+                break
+            if (
+                rpc_err.code() == grpc.StatusCode.FAILED_PRECONDITION
+                and progress.resume_token
+            ):
+                logging.info("The stream cannot be resumed; starting over.")
+                progress = Progress()
+            elif not progress.resume_token:
+                # Without a token the dataset cannot resume streams.
+                progress = Progress()
             if attempt < max_retries:
                 logging.info("Retrying in %d seconds...", retry_delay)
                 time.sleep(retry_delay)
@@ -147,6 +262,141 @@ def run():
     channel.close()
 
 
+class Progress:
+    """The batches received so far, across attempts, and the token that
+    resumes the stream after the last of them"""
+
+    def __init__(self):
+        self.batches = []
+        self.resume_token = b""
+
+
+def get_batches(stub, args, progress, metadata=()):
+    """Read a dataset through GetArrowData, as fast as the server sends it"""
+    # Set a deadline of 30 seconds for the RPC call.
+    request = DataRequest(
+        dataset=args.dataset,
+        columns=[c for c in args.columns.split(",") if c],
+        filter=args.filter,
+        compression=COMPRESSION_CODECS[args.compression],
+        compression_level=args.compression_level,
+        resume_token=progress.resume_token,
+    )
+    response_stream = stub.GetArrowData(request, timeout=30, metadata=metadata)
+    codec = dict(response_stream.initial_metadata()).get(
+        "arrowlink-compression", "none"
+    )
+    logging.info("Server compresses record batches with %s", codec)
+
+    # The server sends one record batch per message; each payload is
+    # a self-contained IPC stream.
+    for response in response_stream:
+        reader = ipc.RecordBatchStreamReader(pa.BufferReader(response.payload))
+        progress.batches.extend(reader)
+        progress.resume_token = response.resume_token
+    log_server_stats(dict(response_stream.trailing_metadata() or ()))
+
+
+class PulledStream(io.RawIOBase):
+    """The IPC stream carried by PullArrowData responses, as a file object"""
+
+    def __init__(self, responses):
+        self.responses = responses
+        self.buf = b""
+        self.summary = None
+        # Resume tokens of the record batches read but not yet decoded
+        self.tokens = collections.deque()
+
+    def readable(self):
+        return True
+
+    def readinto(self, b):
+        while not self.buf:
+            response = next(self.responses, None)
+            if response is None:
+                return 0
+            kind = response.WhichOneof("message")
+            if kind == "schema":
+                self.buf = response.schema.schema
+            elif kind == "dictionary_batch":
+                self.buf = response.dictionary_batch.data
+            elif kind == "record_batch":
+                self.buf = response.record_batch.data
+                self.tokens.append(response.record_batch.resume_token)
+            elif kind == "summary":
+                self.summary = response.summary
+        n = min(len(b), len(self.buf))
+        b[:n] = self.buf[:n]
+        self.buf = self.buf[n:]
+        return n
+
+
+def pull_batches(channel, args, progress, metadata=()):
+    """Read a dataset through PullArrowData, granting credit for one more
+    batch each time one has been decoded, so that at most args.window batches
+    are in flight however slowly they are processed"""
+    logging.info("Calling PullArrowData with a window of %d batches...", args.window)
+    requests = queue.Queue()
+    requests.put(
+        v2.PullRequest(
+            request=v2.DataRequest(
+                dataset=args.dataset,
+                columns=[c for c in args.columns.split(",") if c],
+                filter=args.filter,
+                compression=COMPRESSION_CODECS[args.compression],
+                compression_level=args.compression_level,
+                resume_token=progress.resume_token,
+            )
+        )
+    )
+    requests.put(v2.PullRequest(credit=v2.Credit(batches=args.window)))
+    responses = ArrowDataServiceV2Stub(channel).PullArrowData(
+        iter(requests.get, None), timeout=30, metadata=metadata
+    )
+    try:
+        stream = PulledStream(responses)
+        for batch in ipc.open_stream(stream):
+            progress.batches.append(batch)
+            progress.resume_token = stream.tokens.popleft()
+            requests.put(v2.PullRequest(credit=v2.Credit(batches=1)))
+    finally:
+        requests.put(None)
+    if stream.summary is not None:
+        s = stream.summary
+        logging.info(
+            "Server: generation %.3f ms, serialization %.3f ms (compression %.3f ms), "
+            "%d rows in %d batches, %d bytes",
+            s.generation_ms, s.serialization_ms, s.compression_ms,
+            s.rows, s.batches, s.bytes,
+        )
+
+
+def log_server_stats(trailer):
+    """Log the server's timings and counts for the stream, sent in its trailer"""
+    if "arrowlink-rows" not in trailer:
+        return
+    logging.info(
+        "Server: generation %s ms, serialization %s ms (compression %s ms), "
+        "%s rows in %s batches, %s bytes",
+        trailer.get("arrowlink-generation-ms"),
+        trailer.get("arrowlink-serialization-ms"),
+        trailer.get("arrowlink-compression-ms"),
+        trailer.get("arrowlink-rows"),
+        trailer.get("arrowlink-batches"),
+        trailer.get("arrowlink-bytes"),
+    )
+
+
+def list_datasets(stub, metadata=()):
+    """Log the name, size estimate and schema of every dataset on the server"""
+    response = stub.ListDatasets(Empty(), timeout=30, metadata=metadata)
+    for info in response.datasets:
+        schema = ipc.read_schema(pa.py_buffer(info.schema))
+        logging.info(
+            f"{info.name}: {info.description} (~{info.estimated_rows} rows)\n{schema}"
+        )
+
+
 def visualize_data(df):
     """Generate visualizations for the received data"""
     # Set the style
@@ -24,7 +24,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x12\x64\x61taexchange.proto\x12\x0c\x64\x61taexchange\"\x07\n\x05\x45mpty\"\x1c\n\tArrowData\x12\x0f\n\x07payload\x18\x01 \x01(\x0c\"\x16\n\x03\x41\x63k\x12\x0f\n\x07message\x18\x01 \x01(\t2\x91\x01\n\x10\x41rrowDataService\x12>\n\x0cGetArrowData\x12\x13.dataexchange.Empty\x1a\x17.dataexchange.ArrowData0\x01\x12=\n\rSendArrowData\x12\x17.dataexchange.ArrowData\x1a\x11.dataexchange.Ack(\x01\x42!Z\x1fproto/dataexchange;dataexchangeb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x12\x64\x61taexchange.proto\x12\x0c\x64\x61taexchange\"\x07\n\x05\x45mpty\"\xc8\x01\n\x0b\x44\x61taRequest\x12\x0f\n\x07\x64\x61taset\x18\x01 \x01(\t\x12\x0f\n\x07\x63olumns\x18\x02 \x03(\t\x12\x0e\n\x06\x66ilter\x18\x03 \x01(\t\x12.\n\x0b\x63ompression\x18\x04 \x01(\x0e\x32\x19.dataexchange.Compression\x12\x19\n\x11\x63ompression_level\x18\x05 \x01(\x05\x12&\n\x07\x66raming\x18\x06 \x01(\x0e\x32\x15.dataexchange.Framing\x12\x14\n\x0cresume_token\x18\x07 \x01(\x0c\"!\n\x0e\x44\x61tasetRequest\x12\x0f\n\x07\x64\x61taset\x18\x01 \x01(\t\"\xc4\x01\n\x0b\x44\x61tasetInfo\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x13\n\x0b\x64\x65scription\x18\x02 \x01(\t\x12\x0e\n\x06schema\x18\x03 \x01(\x0c\x12\x16\n\x0e\x65stimated_rows\x18\x04 \x01(\x03\x12\x39\n\x08metadata\x18\x05 \x03(\x0b\x32\'.dataexchange.DatasetInfo.MetadataEntry\x1a/\n\rMetadataEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\":\n\x0b\x44\x61tasetList\x12+\n\x08\x64\x61tasets\x18\x01 \x03(\x0b\x32\x19.dataexchange.DatasetInfo\"2\n\tArrowData\x12\x0f\n\x07payload\x18\x01 \x01(\x0c\x12\x14\n\x0cresume_token\x18\x02 \x01(\x0c\"o\n\x03\x41\x63k\x12\x0f\n\x07message\x18\x01 \x01(\t\x12\x0c\n\x04rows\x18\x02 \x01(\x03\x12\x0f\n\x07\x62\x61tches\x18\x03 \x01(\x03\x12\r\n\x05\x62ytes\x18\x04 \x01(\x03\x12\x19\n\x11rejected_payloads\x18\x05 \x01(\x03\x12\x0e\n\x06\x65rrors\x18\x06 \x03(\t*3\n\x07\x46raming\x12\x12\n\x0e\x46RAMING_STREAM\x10\x00\x12\x14\n\x10\x46RAMING_MESSAGES\x10\x01*q\n\x0b\x43ompression\x12\x1b\n\x17\x43OMPRESSION_UNSPECIFIED\x10\x00\x12\x14\n\x10\x43OMPRESSION_NONE\x10\x01\x12\x19\n\x15\x43OMPRESSION_LZ4_FRAME\x10\x02\x12\x14\n\x10\x43OMPRESSION_ZSTD\x10\x03\x32\xa3\x02\n\x10\x41rrowDataService\x12\x44\n\x0cGetArrowData\x12\x19.dataexchange.DataRequest\x1a\x17.dataexchange.ArrowData0\x01\x12=\n\rSendArrowData\x12\x17.dataexchange.ArrowData\x1a\x11.dataexchange.Ack(\x01\x12>\n\x0cListDatasets\x12\x13.dataexchange.Empty\x1a\x19.dataexchange.DatasetList\x12J\n\x0f\x44\x65scribeDataset\x12\x1c.dataexchange.DatasetRequest\x1a\x19.dataexchange.DatasetInfoB!Z\x1fproto/dataexchange;dataexchangeb\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -32,12 +32,28 @@ _builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'dataexchange_pb2', _globals
 if not _descriptor._USE_C_DESCRIPTORS:
   _globals['DESCRIPTOR']._loaded_options = None
   _globals['DESCRIPTOR']._serialized_options = b'Z\037proto/dataexchange;dataexchange'
+  _globals['_DATASETINFO_METADATAENTRY']._loaded_options = None
+  _globals['_DATASETINFO_METADATAENTRY']._serialized_options = b'8\001'
+  _globals['_FRAMING']._serialized_start=707
+  _globals['_FRAMING']._serialized_end=758
+  _globals['_COMPRESSION']._serialized_start=760
+  _globals['_COMPRESSION']._serialized_end=873
   _globals['_EMPTY']._serialized_start=36
   _globals['_EMPTY']._serialized_end=43
-  _globals['_ARROWDATA']._serialized_start=45
-  _globals['_ARROWDATA']._serialized_end=73
-  _globals['_ACK']._serialized_start=75
-  _globals['_ACK']._serialized_end=97
-  _globals['_ARROWDATASERVICE']._serialized_start=100
-  _globals['_ARROWDATASERVICE']._serialized_end=245
+  _globals['_DATAREQUEST']._serialized_start=46
+  _globals['_DATAREQUEST']._serialized_end=246
+  _globals['_DATASETREQUEST']._serialized_start=248
+  _globals['_DATASETREQUEST']._serialized_end=281
+  _globals['_DATASETINFO']._serialized_start=284
+  _globals['_DATASETINFO']._serialized_end=480
+  _globals['_DATASETINFO_METADATAENTRY']._serialized_start=433
+  _globals['_DATASETINFO_METADATAENTRY']._serialized_end=480
+  _globals['_DATASETLIST']._serialized_start=482
+  _globals['_DATASETLIST']._serialized_end=540
+  _globals['_ARROWDATA']._serialized_start=542
+  _globals['_ARROWDATA']._serialized_end=592
+  _globals['_ACK']._serialized_start=594
+  _globals['_ACK']._serialized_end=705
+  _globals['_ARROWDATASERVICE']._serialized_start=876
+  _globals['_ARROWDATASERVICE']._serialized_end=1167
 # @@protoc_insertion_point(module_scope)
@@ -36,7 +36,7 @@ class ArrowDataServiceStub(object):
         """
         self.GetArrowData = channel.unary_stream(
                 '/dataexchange.ArrowDataService/GetArrowData',
-                request_serializer=dataexchange__pb2.Empty.SerializeToString,
+                request_serializer=dataexchange__pb2.DataRequest.SerializeToString,
                 response_deserializer=dataexchange__pb2.ArrowData.FromString,
                 _registered_method=True)
         self.SendArrowData = channel.stream_unary(
@@ -44,6 +44,16 @@ class ArrowDataServiceStub(object):
                 request_serializer=dataexchange__pb2.ArrowData.SerializeToString,
                 response_deserializer=dataexchange__pb2.Ack.FromString,
                 _registered_method=True)
+        self.ListDatasets = channel.unary_unary(
+                '/dataexchange.ArrowDataService/ListDatasets',
+                request_serializer=dataexchange__pb2.Empty.SerializeToString,
+                response_deserializer=dataexchange__pb2.DatasetList.FromString,
+                _registered_method=True)
+        self.DescribeDataset = channel.unary_unary(
+                '/dataexchange.ArrowDataService/DescribeDataset',
+                request_serializer=dataexchange__pb2.DatasetRequest.SerializeToString,
+                response_deserializer=dataexchange__pb2.DatasetInfo.FromString,
+                _registered_method=True)
 
 
 class ArrowDataServiceServicer(object):
@@ -63,12 +73,26 @@ class ArrowDataServiceServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def ListDatasets(self, request, context):
+        """Lists the datasets registered with the server
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def DescribeDataset(self, request, context):
+        """Describes a single dataset
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_ArrowDataServiceServicer_to_server(servicer, server):
     rpc_method_handlers = {
             'GetArrowData': grpc.unary_stream_rpc_method_handler(
                     servicer.GetArrowData,
-                    request_deserializer=dataexchange__pb2.Empty.FromString,
+                    request_deserializer=dataexchange__pb2.DataRequest.FromString,
                     response_serializer=dataexchange__pb2.ArrowData.SerializeToString,
             ),
             'SendArrowData': grpc.stream_unary_rpc_method_handler(
@@ -76,6 +100,16 @@ def add_ArrowDataServiceServicer_to_server(servicer, server):
                     request_deserializer=dataexchange__pb2.ArrowData.FromString,
                     response_serializer=dataexchange__pb2.Ack.SerializeToString,
             ),
+            'ListDatasets': grpc.unary_unary_rpc_method_handler(
+                    servicer.ListDatasets,
+                    request_deserializer=dataexchange__pb2.Empty.FromString,
+                    response_serializer=dataexchange__pb2.DatasetList.SerializeToString,
+            ),
+            'DescribeDataset': grpc.unary_unary_rpc_method_handler(
+                    servicer.DescribeDataset,
+                    request_deserializer=dataexchange__pb2.DatasetRequest.FromString,
+                    response_serializer=dataexchange__pb2.DatasetInfo.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'dataexchange.ArrowDataService', rpc_method_handlers)
@@ -102,7 +136,7 @@ class ArrowDataService(object):
             request,
             target,
             '/dataexchange.ArrowDataService/GetArrowData',
-            dataexchange__pb2.Empty.SerializeToString,
+            dataexchange__pb2.DataRequest.SerializeToString,
             dataexchange__pb2.ArrowData.FromString,
             options,
             channel_credentials,
@@ -140,3 +174,57 @@ class ArrowDataService(object):
             timeout,
             metadata,
             _registered_method=True)
+
+    @staticmethod
+    def ListDatasets(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/dataexchange.ArrowDataService/ListDatasets',
+            dataexchange__pb2.Empty.SerializeToString,
+            dataexchange__pb2.DatasetList.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def DescribeDataset(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/dataexchange.ArrowDataService/DescribeDataset',
+            dataexchange__pb2.DatasetRequest.SerializeToString,
+            dataexchange__pb2.DatasetInfo.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
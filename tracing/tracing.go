@@ -0,0 +1,97 @@
+// Package tracing configures OpenTelemetry tracing for ArrowLink. Setup
+// installs a global tracer provider and the W3C trace context propagator;
+// the grpcserver and arrow packages create their spans through the global
+// provider, so they record nothing until Setup has been called.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Exporters accepted in Config.Exporter.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// DefaultOTLPEndpoint is the address of a local OpenTelemetry collector.
+const DefaultOTLPEndpoint = "localhost:4317"
+
+// Config selects where spans are exported.
+type Config struct {
+	// Exporter is ExporterStdout, ExporterOTLP or ExporterNone (or empty),
+	// which disables tracing.
+	Exporter string
+	// Endpoint is the OTLP/gRPC collector address. Defaults to
+	// DefaultOTLPEndpoint.
+	Endpoint string
+	// Insecure connects to the collector without TLS, as local collectors
+	// usually expect.
+	Insecure bool
+	// ServiceName identifies this process in traces. Defaults to "arrowlink".
+	ServiceName string
+	// SampleRatio is the fraction of new traces recorded; traces started by
+	// a sampled caller are always recorded. Zero records every trace.
+	SampleRatio float64
+}
+
+// Setup installs the global tracer provider and propagator described by cfg.
+// The returned function flushes pending spans and shuts the exporter down.
+// With tracing disabled it only installs the propagator, so trace context
+// still passes through, and returns a no-op shutdown.
+func Setup(ctx context.Context, cfg Config) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	case ExporterOTLP:
+		endpoint := cfg.Endpoint
+		if endpoint == "" {
+			endpoint = DefaultOTLPEndpoint
+		}
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q (want %s, %s or %s)", cfg.Exporter, ExporterStdout, ExporterOTLP, ExporterNone)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create %s trace exporter: %w", cfg.Exporter, err)
+	}
+
+	name := cfg.ServiceName
+	if name == "" {
+		name = "arrowlink"
+	}
+	sampler := sdktrace.AlwaysSample()
+	if cfg.SampleRatio > 0 && cfg.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(cfg.SampleRatio)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", name))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}